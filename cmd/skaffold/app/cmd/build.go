@@ -18,8 +18,10 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"io/ioutil"
+	"strings"
 
 	"github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/flags"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
@@ -30,6 +32,7 @@ import (
 
 var (
 	quietFlag       bool
+	buildOutputFile string
 	buildFormatFlag = flags.NewTemplateFlag("{{range .Builds}}{{.ImageName}} -> {{.Tag}}\n{{end}}", BuildOutput{})
 )
 
@@ -48,6 +51,7 @@ func NewCmdBuild(out io.Writer) *cobra.Command {
 	cmd.Flags().StringArrayVarP(&opts.TargetImages, "build-image", "b", nil, "Choose which artifacts to build. Artifacts with image names that contain the expression will be built only. Default is to build sources for all artifacts")
 	cmd.Flags().BoolVarP(&quietFlag, "quiet", "q", false, "Suppress the build output and print image built on success")
 	cmd.Flags().VarP(buildFormatFlag, "output", "o", buildFormatFlag.Usage())
+	cmd.Flags().StringVar(&buildOutputFile, "file-output", "", "Filename to write build images to, to be used by 'skaffold deploy --build-artifacts'")
 	return cmd
 }
 
@@ -56,6 +60,17 @@ type BuildOutput struct {
 	Builds []build.Artifact
 }
 
+// buildFile is the content of the file written with `--file-output`.
+type buildFile struct {
+	Builds []builtImage `json:"builds"`
+}
+
+type builtImage struct {
+	ImageName string `json:"imageName"`
+	Tag       string `json:"tag"`
+	Digest    string `json:"digest,omitempty"`
+}
+
 func runBuild(out io.Writer) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -87,5 +102,59 @@ func runBuild(out io.Writer) error {
 	if err := buildFormatFlag.Template().Execute(out, cmdOut); err != nil {
 		return errors.Wrap(err, "executing template")
 	}
+
+	if buildOutputFile != "" {
+		if err := writeBuildOutput(buildOutputFile, bRes); err != nil {
+			return errors.Wrap(err, "writing build output")
+		}
+	}
+
 	return nil
 }
+
+func writeBuildOutput(filename string, builds []build.Artifact) error {
+	var file buildFile
+	for _, b := range builds {
+		image := builtImage{
+			ImageName: b.ImageName,
+			Tag:       b.Tag,
+		}
+		if parts := strings.SplitN(b.Tag, "@", 2); len(parts) == 2 {
+			image.Digest = parts[1]
+		}
+		file.Builds = append(file.Builds, image)
+	}
+
+	contents, err := json.Marshal(file)
+	if err != nil {
+		return errors.Wrap(err, "marshalling build output")
+	}
+
+	return ioutil.WriteFile(filename, contents, 0644)
+}
+
+func readBuildOutput(filename string) ([]build.Artifact, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading build output")
+	}
+
+	var file buildFile
+	if err := json.Unmarshal(contents, &file); err != nil {
+		return nil, errors.Wrapf(err, "parsing build output %s", filename)
+	}
+
+	var builds []build.Artifact
+	for _, image := range file.Builds {
+		tag := image.Tag
+		if image.Digest != "" && !strings.Contains(tag, "@") {
+			tag += "@" + image.Digest
+		}
+		builds = append(builds, build.Artifact{
+			ImageName: image.ImageName,
+			Tag:       tag,
+		})
+	}
+
+	return builds, nil
+}
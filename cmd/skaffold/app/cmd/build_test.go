@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/flags"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestBuildOutputFile(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	builds := []build.Artifact{
+		{ImageName: "gcr.io/k8s-skaffold/app", Tag: "gcr.io/k8s-skaffold/app:v1@sha256:abac"},
+		{ImageName: "gcr.io/k8s-skaffold/base", Tag: "gcr.io/k8s-skaffold/base:v1"},
+	}
+
+	err := writeBuildOutput(tmpDir.Path("build.json"), builds)
+	testutil.CheckError(t, false, err)
+
+	contents, err := ioutil.ReadFile(tmpDir.Path("build.json"))
+	testutil.CheckErrorAndDeepEqual(t, false, err, `{"builds":[`+
+		`{"imageName":"gcr.io/k8s-skaffold/app","tag":"gcr.io/k8s-skaffold/app:v1@sha256:abac","digest":"sha256:abac"},`+
+		`{"imageName":"gcr.io/k8s-skaffold/base","tag":"gcr.io/k8s-skaffold/base:v1"}]}`, string(contents))
+
+	read, err := readBuildOutput(tmpDir.Path("build.json"))
+	testutil.CheckErrorAndDeepEqual(t, false, err, builds, read)
+}
+
+func TestReadBuildOutputFileWithDigest(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("build.json", `{"builds":[{"imageName":"app","tag":"app:v1","digest":"sha256:abac"}]}`)
+
+	read, err := readBuildOutput(tmpDir.Path("build.json"))
+	testutil.CheckErrorAndDeepEqual(t, false, err, []build.Artifact{{ImageName: "app", Tag: "app:v1@sha256:abac"}}, read)
+}
+
+// The output of `skaffold build -o '{{json .}}'` must not change.
+func TestBuildOutputTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	err := flags.NewTemplateFlag("{{json .}}", BuildOutput{}).Template().Execute(&buf, BuildOutput{
+		Builds: []build.Artifact{{ImageName: "app", Tag: "app:v1"}},
+	})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, `{"Builds":[{"ImageName":"app","Tag":"app:v1"}]}`, buf.String())
+}
+
+func TestReadInvalidBuildOutputFile(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("build.json", "invalid")
+
+	_, err := readBuildOutput(tmpDir.Path("build.json"))
+	testutil.CheckError(t, true, err)
+
+	_, err = readBuildOutput(tmpDir.Path("missing.json"))
+	testutil.CheckError(t, true, err)
+}
@@ -148,6 +148,11 @@ func AddRunDevFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", "", "Run deployments in the specified namespace")
 	cmd.Flags().StringVarP(&opts.DefaultRepo, "default-repo", "d", "", "Default repository value (overrides global config)")
 	cmd.Flags().BoolVar(&opts.SkipTests, "skip-tests", false, "Whether to skip the tests after building")
+	cmd.Flags().StringVar(&opts.TestReport, "test-report", "", "Write the test results to this file, as JSON if its extension is .json or as JUnit XML otherwise")
+	cmd.Flags().BoolVar(&opts.CacheArtifacts, "cache-artifacts", false, "Set to true to skip the build of artifacts whose dependencies haven't changed")
+	cmd.Flags().StringVar(&opts.CacheFile, "cache-file", "", "Specify the location of the artifact cache file (default $HOME/.skaffold/cache)")
+	cmd.Flags().BoolVar(&opts.CacheTests, "cache-tests", false, "Set to true to skip the tests of images that already passed the same tests")
+	cmd.Flags().StringVar(&opts.TestCacheFile, "test-cache-file", "", "Specify the location of the test cache file (default $HOME/.skaffold/test-cache)")
 }
 
 func SetUpLogs(out io.Writer, level string) error {
@@ -29,4 +29,5 @@ type ContextConfig struct {
 	Kubecontext  string `yaml:"kube-context,omitempty"`
 	DefaultRepo  string `yaml:"default-repo,omitempty"`
 	LocalCluster *bool  `yaml:"local-cluster,omitempty"`
+	LoadImages   *bool  `yaml:"load-images,omitempty"`
 }
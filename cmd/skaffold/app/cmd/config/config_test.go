@@ -120,6 +120,27 @@ func TestSetAndUnsetConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "set load images",
+			key:         "load-images",
+			value:       "false",
+			kubecontext: "kind-dev",
+			expectedSetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext: "kind-dev",
+						LoadImages:  util.BoolPtr(false),
+					},
+				},
+			},
+			expectedUnsetCfg: &Config{
+				ContextConfigs: []*ContextConfig{
+					{
+						Kubecontext: "kind-dev",
+					},
+				},
+			},
+		},
 		{
 			name:         "set invalid local cluster",
 			key:          "local-cluster",
@@ -223,3 +244,29 @@ func TestSetAndUnsetConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalClusterTypes(t *testing.T) {
+	var tests = []struct {
+		kubeContext   string
+		expectedKind  string
+		expectedK3d   string
+		expectedLocal bool
+	}{
+		{kubeContext: "kind-dev", expectedKind: "dev", expectedLocal: true},
+		{kubeContext: "kubernetes-admin@kind", expectedKind: "kind", expectedLocal: true},
+		{kubeContext: "k3d-dev", expectedK3d: "dev", expectedLocal: true},
+		{kubeContext: "minikube", expectedLocal: true},
+		{kubeContext: "gke_project_zone_cluster"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.kubeContext, func(t *testing.T) {
+			kind, _ := KindCluster(test.kubeContext)
+			k3d, _ := K3dCluster(test.kubeContext)
+
+			testutil.CheckDeepEqual(t, test.expectedKind, kind)
+			testutil.CheckDeepEqual(t, test.expectedK3d, k3d)
+			testutil.CheckDeepEqual(t, test.expectedLocal, isDefaultLocal(test.kubeContext))
+		})
+	}
+}
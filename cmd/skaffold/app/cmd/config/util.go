@@ -19,6 +19,7 @@ package config
 import (
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
@@ -199,7 +200,64 @@ func GetLocalCluster() (bool, error) {
 	return localCluster, nil
 }
 
+// GetLoadImages tells if images built locally should be side-loaded into the
+// cluster instead of being pushed. This is only supported for kind and k3d
+// clusters and defaults to true for those.
+func GetLoadImages() (bool, error) {
+	cfg, err := GetConfigForKubectx()
+	if err != nil {
+		return false, errors.Wrap(err, "retrieving global config")
+	}
+
+	loadImages := true
+	if cfg != nil && cfg.LoadImages != nil {
+		loadImages = *cfg.LoadImages
+	} else {
+		// if no value is set for this cluster, fall back to the global setting
+		globalCfg, err := GetGlobalConfig()
+		if err != nil {
+			return false, errors.Wrap(err, "retrieving global config")
+		}
+		if globalCfg != nil && globalCfg.LoadImages != nil {
+			loadImages = *globalCfg.LoadImages
+		}
+	}
+
+	_, isKind := KindCluster(kubecontext)
+	_, isK3d := K3dCluster(kubecontext)
+
+	return loadImages && (isKind || isK3d), nil
+}
+
+// KindCluster returns the name of the kind cluster a kube-context points to.
+func KindCluster(kubeContext string) (string, bool) {
+	switch {
+	case strings.HasPrefix(kubeContext, constants.KindContextPrefix):
+		return strings.TrimPrefix(kubeContext, constants.KindContextPrefix), true
+	case strings.HasSuffix(kubeContext, "@kind"):
+		// Contexts created by older versions of kind
+		return "kind", true
+	default:
+		return "", false
+	}
+}
+
+// K3dCluster returns the name of the k3d cluster a kube-context points to.
+func K3dCluster(kubeContext string) (string, bool) {
+	if strings.HasPrefix(kubeContext, constants.K3dContextPrefix) {
+		return strings.TrimPrefix(kubeContext, constants.K3dContextPrefix), true
+	}
+	return "", false
+}
+
 func isDefaultLocal(kubeContext string) bool {
+	if _, isKind := KindCluster(kubeContext); isKind {
+		return true
+	}
+	if _, isK3d := K3dCluster(kubeContext); isK3d {
+		return true
+	}
+
 	return kubeContext == constants.DefaultMinikubeContext ||
 		kubeContext == constants.DefaultDockerForDesktopContext ||
 		kubeContext == constants.DefaultDockerDesktopContext
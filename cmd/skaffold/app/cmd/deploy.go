@@ -17,11 +17,15 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"io"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+var buildArtifactsFile string
+
 // NewCmdDeploy describes the CLI command to deploy artifacts.
 func NewCmdDeploy(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
@@ -29,13 +33,45 @@ func NewCmdDeploy(out io.Writer) *cobra.Command {
 		Short: "Deploys the artifacts",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Same actions as `skaffold run`, but with pre-built images.
 			opts.Command = "deploy"
+			if buildArtifactsFile != "" {
+				return runDeploy(out)
+			}
+
+			// Same actions as `skaffold run`, but with pre-built images.
 			return run(out)
 		},
 	}
 	AddRunDevFlags(cmd)
 	AddRunDeployFlags(cmd)
 	cmd.Flags().StringSliceVar(&opts.PreBuiltImages, "images", nil, "A list of pre-built images to deploy")
+	cmd.Flags().StringVar(&buildArtifactsFile, "build-artifacts", "", "Filepath containing build output, as written by 'skaffold build --file-output'. The listed images are deployed without being rebuilt")
 	return cmd
 }
+
+// runDeploy deploys the artifacts listed in a build output file.
+func runDeploy(out io.Writer) error {
+	if len(opts.PreBuiltImages) > 0 {
+		return errors.New("--images and --build-artifacts can't be used together")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	catchCtrlC(cancel)
+
+	builds, err := readBuildOutput(buildArtifactsFile)
+	if err != nil {
+		return err
+	}
+
+	runner, config, err := newRunner(opts)
+	if err != nil {
+		return errors.Wrap(err, "creating runner")
+	}
+
+	if err := runner.DeployAndTest(ctx, out, builds); err != nil {
+		return err
+	}
+
+	return runner.TailLogs(ctx, out, config.Build.Artifacts, builds)
+}
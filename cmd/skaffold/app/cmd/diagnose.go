@@ -156,6 +156,12 @@ func typeOfArtifact(a *latest.Artifact) string {
 		return "Jib Gradle artifact"
 	case a.JibMavenArtifact != nil:
 		return "Jib Maven artifact"
+	case a.CustomArtifact != nil:
+		return "Custom artifact"
+	case a.BuildpackArtifact != nil:
+		return "Buildpacks artifact"
+	case a.GoImageArtifact != nil:
+		return "Go image artifact"
 	default:
 		return "Unknown artifact"
 	}
@@ -27,6 +27,7 @@ import (
 	"strings"
 
 	"github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/tips"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/buildpacks"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema"
@@ -47,6 +48,12 @@ import (
 // an image we parse out from a kubernetes manifest
 const NoDockerfile = "None (image not built from these sources)"
 
+// ignoredDirs are not searched for Dockerfiles, buildpacks projects or manifests.
+var ignoredDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
 var (
 	composeFile  string
 	cliArtifacts []string
@@ -68,7 +75,7 @@ func NewCmdInit(out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVar(&skipBuild, "skip-build", false, "Skip generating build artifacts in Skaffold config")
 	cmd.Flags().BoolVar(&force, "force", false, "Force the generation of the Skaffold config")
 	cmd.Flags().StringVar(&composeFile, "compose-file", "", "Initialize from a docker-compose file")
-	cmd.Flags().StringArrayVarP(&cliArtifacts, "artifact", "a", nil, "'='-delimited dockerfile/image pair to generate build artifact\n(example: --artifact=/web/Dockerfile.web=gcr.io/web-project/image)")
+	cmd.Flags().StringArrayVarP(&cliArtifacts, "artifact", "a", nil, "'='-delimited Dockerfile or buildpacks project file/image pair to generate build artifact\n(example: --artifact=/web/Dockerfile.web=gcr.io/web-project/image)")
 	return cmd
 }
 
@@ -84,9 +91,12 @@ func doInit(out io.Writer) error {
 		}
 	}
 
-	var potentialConfigs, k8sConfigs, dockerfiles, images []string
+	var potentialConfigs, k8sConfigs, dockerfiles, projects, images []string
 	err := filepath.Walk(rootDir, func(path string, f os.FileInfo, e error) error {
 		if f.IsDir() {
+			if ignoredDirs[f.Name()] {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 		if strings.HasPrefix(path, ".") {
@@ -100,11 +110,16 @@ func doInit(out io.Writer) error {
 			logrus.Infof("existing dockerfile found: %s", path)
 			dockerfiles = append(dockerfiles, path)
 		}
+		if buildpacks.IsSupportedProject(path) {
+			logrus.Infof("buildpacks project found: %s", path)
+			projects = append(projects, path)
+		}
 		return nil
 	})
 	if err != nil {
 		return err
 	}
+	builders := selectBuilders(dockerfiles, projects)
 
 	for _, file := range potentialConfigs {
 		if !force {
@@ -125,11 +140,11 @@ func doInit(out io.Writer) error {
 		}
 	}
 
-	var pairs []dockerfilePair
+	var pairs []builderImagePair
 	// conditionally generate build artifacts
 	if !skipBuild {
-		if len(dockerfiles) == 0 {
-			return errors.New("one or more valid Dockerfiles must be present to run skaffold; please provide at least one Dockerfile and try again")
+		if len(builders) == 0 {
+			return errors.New("one or more valid Dockerfiles or buildpacks projects must be present to run skaffold; please provide at least one Dockerfile and try again")
 		}
 
 		if len(k8sConfigs) == 0 {
@@ -142,7 +157,7 @@ func doInit(out io.Writer) error {
 				return errors.Wrap(err, "processing cli artifacts")
 			}
 		} else {
-			pairs = resolveDockerfileImages(dockerfiles, images)
+			pairs = resolveBuilderImages(builders, images)
 		}
 	}
 
@@ -189,80 +204,108 @@ func doInit(out io.Writer) error {
 	return nil
 }
 
-func processCliArtifacts(artifacts []string) ([]dockerfilePair, error) {
-	var pairs []dockerfilePair
+// selectBuilders keeps every Dockerfile and only one buildpacks project
+// per directory that doesn't already have a Dockerfile.
+func selectBuilders(dockerfiles, projects []string) []string {
+	builders := dockerfiles
+
+	seen := map[string]bool{}
+	for _, dockerfile := range dockerfiles {
+		seen[filepath.Dir(dockerfile)] = true
+	}
+	for _, project := range projects {
+		dir := filepath.Dir(project)
+		if seen[dir] {
+			logrus.Debugf("ignoring buildpacks project %s", project)
+			continue
+		}
+		seen[dir] = true
+		builders = append(builders, project)
+	}
+
+	return builders
+}
+
+func processCliArtifacts(artifacts []string) ([]builderImagePair, error) {
+	var pairs []builderImagePair
 	for _, artifact := range artifacts {
 		parts := strings.Split(artifact, "=")
 		if len(parts) != 2 {
 			return nil, fmt.Errorf("malformed artifact provided: %s", artifact)
 		}
-		pairs = append(pairs, dockerfilePair{
-			Dockerfile: parts[0],
-			ImageName:  parts[1],
+		pairs = append(pairs, builderImagePair{
+			Builder:   parts[0],
+			ImageName: parts[1],
 		})
 	}
 	return pairs, nil
 }
 
 // For each image parsed from all k8s manifests, prompt the user for
-// the dockerfile that builds the referenced image
-func resolveDockerfileImages(dockerfiles []string, images []string) []dockerfilePair {
-	// if we only have 1 image and 1 dockerfile, don't bother prompting
-	if len(images) == 1 && len(dockerfiles) == 1 {
-		return []dockerfilePair{{
-			Dockerfile: dockerfiles[0],
-			ImageName:  images[0],
+// the dockerfile or buildpacks project that builds the referenced image
+func resolveBuilderImages(builders []string, images []string) []builderImagePair {
+	// if we only have 1 image and 1 builder, don't bother prompting
+	if len(images) == 1 && len(builders) == 1 {
+		return []builderImagePair{{
+			Builder:   builders[0],
+			ImageName: images[0],
 		}}
 	}
-	pairs := []dockerfilePair{}
+	pairs := []builderImagePair{}
 	for {
 		if len(images) == 0 {
 			break
 		}
 		image := images[0]
-		pair := promptUserForDockerfile(image, dockerfiles)
-		if pair.Dockerfile != NoDockerfile {
+		pair := promptUserForBuilder(image, builders)
+		if pair.Builder != NoDockerfile {
 			pairs = append(pairs, pair)
-			dockerfiles = util.RemoveFromSlice(dockerfiles, pair.Dockerfile)
+			builders = util.RemoveFromSlice(builders, pair.Builder)
 		}
 		images = util.RemoveFromSlice(images, pair.ImageName)
 	}
-	if len(dockerfiles) > 0 {
-		logrus.Warnf("unused dockerfiles found in repository: %v", dockerfiles)
+	if len(builders) > 0 {
+		logrus.Warnf("unused dockerfiles or buildpacks projects found in repository: %v", builders)
 	}
 	return pairs
 }
 
-func promptUserForDockerfile(image string, dockerfiles []string) dockerfilePair {
-	var selectedDockerfile string
-	options := append(dockerfiles, NoDockerfile)
+func promptUserForBuilder(image string, builders []string) builderImagePair {
+	var selectedBuilder string
+	options := append(builders, NoDockerfile)
 	prompt := &survey.Select{
-		Message:  fmt.Sprintf("Choose the dockerfile to build image %s", image),
+		Message:  fmt.Sprintf("Choose the dockerfile or buildpacks project to build image %s", image),
 		Options:  options,
 		PageSize: 15,
 	}
-	survey.AskOne(prompt, &selectedDockerfile, nil)
-	return dockerfilePair{
-		Dockerfile: selectedDockerfile,
-		ImageName:  image,
+	survey.AskOne(prompt, &selectedBuilder, nil)
+	return builderImagePair{
+		Builder:   selectedBuilder,
+		ImageName: image,
 	}
 }
 
-func processBuildArtifacts(pairs []dockerfilePair) latest.BuildConfig {
+func processBuildArtifacts(pairs []builderImagePair) latest.BuildConfig {
 	var config latest.BuildConfig
 
 	if len(pairs) > 0 {
 		var artifacts []*latest.Artifact
 		for _, pair := range pairs {
-			workspace := filepath.Dir(pair.Dockerfile)
-			dockerfilePath := filepath.Base(pair.Dockerfile)
+			workspace := filepath.Dir(pair.Builder)
+			dockerfilePath := filepath.Base(pair.Builder)
 			a := &latest.Artifact{
 				ImageName: pair.ImageName,
 			}
 			if workspace != "." {
 				a.Workspace = workspace
 			}
-			if dockerfilePath != constants.DefaultDockerfilePath {
+			if buildpacks.IsSupportedProject(pair.Builder) {
+				a.ArtifactType = latest.ArtifactType{
+					BuildpackArtifact: &latest.BuildpackArtifact{
+						Builder: buildpacks.DefaultBuilder,
+					},
+				}
+			} else if dockerfilePath != constants.DefaultDockerfilePath {
 				a.ArtifactType = latest.ArtifactType{
 					DockerArtifact: &latest.DockerArtifact{
 						DockerfilePath: dockerfilePath,
@@ -276,7 +319,7 @@ func processBuildArtifacts(pairs []dockerfilePair) latest.BuildConfig {
 	return config
 }
 
-func generateSkaffoldPipeline(k8sConfigs []string, dockerfilePairs []dockerfilePair) ([]byte, error) {
+func generateSkaffoldPipeline(k8sConfigs []string, builderImagePairs []builderImagePair) ([]byte, error) {
 	// if we're here, the user has no skaffold yaml so we need to generate one
 	// if the user doesn't have any k8s yamls, generate one for each dockerfile
 	logrus.Info("generating skaffold config")
@@ -289,7 +332,7 @@ func generateSkaffoldPipeline(k8sConfigs []string, dockerfilePairs []dockerfileP
 		return nil, errors.Wrap(err, "generating default pipeline")
 	}
 
-	pipeline.Build = processBuildArtifacts(dockerfilePairs)
+	pipeline.Build = processBuildArtifacts(builderImagePairs)
 	pipeline.Deploy = latest.DeployConfig{
 		DeployType: latest.DeployType{
 			KubectlDeploy: &latest.KubectlDeploy{
@@ -368,7 +411,9 @@ func parseImagesFromYaml(doc interface{}) []string {
 	return images
 }
 
-type dockerfilePair struct {
-	Dockerfile string
-	ImageName  string
+// builderImagePair associates an image with the Dockerfile, or the
+// buildpacks project file, it is built from.
+type builderImagePair struct {
+	Builder   string
+	ImageName string
 }
@@ -44,6 +44,10 @@ build:
   - image: docker/image
     docker:
       dockerfile: dockerfile.test
+  - image: web/image
+    context: web
+    buildpacks:
+      builder: heroku/buildpacks
 deploy:
   kubectl:
     manifests:
@@ -51,12 +55,50 @@ deploy:
 `, latest.Version)
 
 	k8sConfigs := []string{"k8s/deployment.yaml"}
-	dockerfilePairs := []dockerfilePair{{
-		Dockerfile: "dockerfile.test",
-		ImageName:  "docker/image",
+	builderImagePairs := []builderImagePair{{
+		Builder:   "dockerfile.test",
+		ImageName: "docker/image",
+	}, {
+		Builder:   "web/package.json",
+		ImageName: "web/image",
 	}}
 
-	buf, err := generateSkaffoldPipeline(k8sConfigs, dockerfilePairs)
+	buf, err := generateSkaffoldPipeline(k8sConfigs, builderImagePairs)
 
 	testutil.CheckErrorAndDeepEqual(t, false, err, expectedYaml, string(buf))
 }
+
+func TestSelectBuilders(t *testing.T) {
+	var tests = []struct {
+		description string
+		dockerfiles []string
+		projects    []string
+		expected    []string
+	}{
+		{
+			description: "dockerfile and buildpacks project in the same directory",
+			dockerfiles: []string{"Dockerfile"},
+			projects:    []string{"package.json"},
+			expected:    []string{"Dockerfile"},
+		},
+		{
+			description: "buildpacks project without dockerfile",
+			dockerfiles: []string{"Dockerfile"},
+			projects:    []string{"web/package.json"},
+			expected:    []string{"Dockerfile", "web/package.json"},
+		},
+		{
+			description: "several project files in the same directory",
+			projects:    []string{"web/Gemfile", "web/package.json"},
+			expected:    []string{"web/Gemfile"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			builders := selectBuilders(test.dockerfiles, test.projects)
+
+			testutil.CheckDeepEqual(t, test.expected, builders)
+		})
+	}
+}
@@ -41,3 +41,9 @@ type Builder interface {
 
 	DependenciesForArtifact(ctx context.Context, artifact *latest.Artifact) ([]string, error)
 }
+
+// Stopper is implemented by builders that keep resources running between
+// builds, like a builder pod. Stop releases those resources.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
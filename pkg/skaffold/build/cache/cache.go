@@ -0,0 +1,266 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	defaultCacheDir  = ".skaffold"
+	defaultCacheFile = "cache"
+)
+
+var (
+	// For testing
+	remoteDigest = docker.RemoteDigest
+	addTag       = docker.AddTag
+)
+
+// ImageDetails holds the digest and the ID of an image that was previously built.
+type ImageDetails struct {
+	Digest string `yaml:"digest,omitempty"`
+	ID     string `yaml:"id,omitempty"`
+}
+
+// ArtifactCache maps the hash of an artifact's inputs to the image that was built from them.
+type ArtifactCache map[string]ImageDetails
+
+// Builder wraps a build.Builder and skips the build of artifacts
+// whose inputs haven't changed since the image was last built.
+type Builder struct {
+	build.Builder
+
+	artifactCache ArtifactCache
+	cacheFile     string
+	localDocker   docker.LocalDaemon
+	inputs        *tag.InputDigestTagger
+}
+
+// NewBuilder returns a Builder that caches the artifacts built by the given builder.
+// If cacheFile is empty, the cache is stored in `~/.skaffold/cache`.
+func NewBuilder(builder build.Builder, artifacts []*latest.Artifact, cacheFile string) (*Builder, error) {
+	cacheFile, err := resolveCacheFile(cacheFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving cache file location")
+	}
+
+	artifactCache, err := retrieveArtifactCache(cacheFile)
+	if err != nil {
+		logrus.Warnf("Unable to read artifact cache, starting with an empty cache: %s", err)
+		artifactCache = ArtifactCache{}
+	}
+
+	localDocker, err := docker.NewAPIClient()
+	if err != nil {
+		logrus.Debugf("Unable to get docker client, local images won't be looked up: %s", err)
+		localDocker = nil
+	}
+
+	return &Builder{
+		Builder:       builder,
+		artifactCache: artifactCache,
+		cacheFile:     cacheFile,
+		localDocker:   localDocker,
+		inputs:        tag.NewInputDigestTagger(artifacts, builder.DependenciesForArtifact),
+	}, nil
+}
+
+// Build builds the artifacts that are not found in the cache and
+// returns the cached images for the others.
+func (b *Builder) Build(ctx context.Context, out io.Writer, tags tag.ImageTags, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	hashes := map[string]string{}
+	cached := map[string]build.Artifact{}
+
+	var needToBuild []*latest.Artifact
+	for _, a := range artifacts {
+		hash, err := b.inputs.InputDigest(ctx, a)
+		if err != nil {
+			logrus.Warnf("Unable to compute the inputs hash of %s, it will be rebuilt: %s", a.ImageName, err)
+			needToBuild = append(needToBuild, a)
+			continue
+		}
+		hashes[a.ImageName] = hash
+
+		if fqn, found := b.retrieveCachedArtifact(ctx, a, tags[a.ImageName], hash); found {
+			color.Default.Fprintf(out, "Found [%s] in cache, skipping build\n", a.ImageName)
+			cached[a.ImageName] = build.Artifact{
+				ImageName: a.ImageName,
+				Tag:       fqn,
+			}
+			continue
+		}
+
+		needToBuild = append(needToBuild, a)
+	}
+
+	built := map[string]build.Artifact{}
+	if len(needToBuild) > 0 {
+		bRes, err := b.Builder.Build(ctx, out, tags, needToBuild)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range bRes {
+			built[a.ImageName] = a
+		}
+
+		if err := b.retainArtifacts(ctx, bRes, hashes); err != nil {
+			logrus.Warnf("Unable to save the artifact cache: %s", err)
+		}
+	}
+
+	// Keep the order in which artifacts were given.
+	var res []build.Artifact
+	for _, a := range artifacts {
+		if c, present := cached[a.ImageName]; present {
+			res = append(res, c)
+		} else if artifact, present := built[a.ImageName]; present {
+			res = append(res, artifact)
+		}
+	}
+
+	return res, nil
+}
+
+// retrieveCachedArtifact looks for an image previously built from the same inputs,
+// either in a remote registry or in the local docker daemon. The image is retagged
+// with the newly generated tag.
+func (b *Builder) retrieveCachedArtifact(ctx context.Context, a *latest.Artifact, newTag, hash string) (string, bool) {
+	details, present := b.artifactCache[hash]
+	if !present {
+		return "", false
+	}
+
+	if details.Digest != "" {
+		// The image was pushed to the repository of the tag, which
+		// can differ from the image name when a default repo is set.
+		parsed, err := docker.ParseReference(newTag)
+		if err != nil {
+			logrus.Debugf("Unable to parse %s: %s", newTag, err)
+			return "", false
+		}
+
+		ref := parsed.BaseName + "@" + details.Digest
+		if _, err := remoteDigest(ref); err == nil {
+			if err := addTag(ref, newTag); err != nil {
+				logrus.Debugf("Unable to tag %s with %s: %s", ref, newTag, err)
+				return "", false
+			}
+			return newTag + "@" + details.Digest, true
+		}
+	}
+
+	if details.ID != "" && b.localDocker != nil {
+		imageID, err := b.localDocker.ImageID(ctx, details.ID)
+		if err != nil || imageID == "" {
+			return "", false
+		}
+
+		// Same unique tag as the one used by the local builder.
+		uniqueTag := a.ImageName + ":" + strings.TrimPrefix(imageID, "sha256:")
+		for _, ref := range []string{newTag, uniqueTag} {
+			if err := b.localDocker.Tag(ctx, imageID, ref); err != nil {
+				logrus.Debugf("Unable to tag %s with %s: %s", imageID, ref, err)
+				return "", false
+			}
+		}
+		return uniqueTag, true
+	}
+
+	return "", false
+}
+
+// retainArtifacts records the freshly built artifacts in the cache and saves it.
+func (b *Builder) retainArtifacts(ctx context.Context, bRes []build.Artifact, hashes map[string]string) error {
+	for _, a := range bRes {
+		hash, present := hashes[a.ImageName]
+		if !present {
+			continue
+		}
+
+		var details ImageDetails
+		if i := strings.Index(a.Tag, "@"); i != -1 {
+			details.Digest = a.Tag[i+1:]
+		} else if b.localDocker != nil {
+			imageID, err := b.localDocker.ImageID(ctx, a.Tag)
+			if err != nil {
+				return errors.Wrapf(err, "getting imageID for %s", a.Tag)
+			}
+			details.ID = imageID
+		}
+
+		if details.Digest == "" && details.ID == "" {
+			continue
+		}
+		b.artifactCache[hash] = details
+	}
+
+	return saveArtifactCache(b.cacheFile, b.artifactCache)
+}
+
+func resolveCacheFile(cacheFile string) (string, error) {
+	if cacheFile != "" {
+		return cacheFile, util.VerifyOrCreateFile(cacheFile)
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", errors.Wrap(err, "retrieving home directory")
+	}
+
+	defaultFile := filepath.Join(home, defaultCacheDir, defaultCacheFile)
+	return defaultFile, util.VerifyOrCreateFile(defaultFile)
+}
+
+func retrieveArtifactCache(cacheFile string) (ArtifactCache, error) {
+	contents, err := ioutil.ReadFile(cacheFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading cache file")
+	}
+
+	cache := ArtifactCache{}
+	if err := yaml.Unmarshal(contents, &cache); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling cache file")
+	}
+
+	return cache, nil
+}
+
+func saveArtifactCache(cacheFile string, cache ArtifactCache) error {
+	contents, err := yaml.Marshal(cache)
+	if err != nil {
+		return errors.Wrap(err, "marshalling cache")
+	}
+
+	return ioutil.WriteFile(cacheFile, contents, 0644)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type fakeBuilder struct {
+	dependencies []string
+	built        []string
+	push         bool
+}
+
+func (f *fakeBuilder) Labels() map[string]string { return nil }
+
+func (f *fakeBuilder) DependenciesForArtifact(ctx context.Context, artifact *latest.Artifact) ([]string, error) {
+	return f.dependencies, nil
+}
+
+func (f *fakeBuilder) Build(ctx context.Context, out io.Writer, tags tag.ImageTags, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	var builds []build.Artifact
+	for _, a := range artifacts {
+		f.built = append(f.built, a.ImageName)
+
+		fqn := tags[a.ImageName]
+		if f.push {
+			fqn += "@sha256:" + a.ImageName
+		}
+
+		builds = append(builds, build.Artifact{
+			ImageName: a.ImageName,
+			Tag:       fqn,
+		})
+	}
+	return builds, nil
+}
+
+func TestCacheBuild(t *testing.T) {
+	defer func(d func(string) (string, error), a func(string, string) error) {
+		remoteDigest = d
+		addTag = a
+	}(remoteDigest, addTag)
+
+	localImages := map[string]string{
+		"image1:tag": "sha256:id1",
+		"sha256:id1": "sha256:id1",
+		"image2:tag": "sha256:id2",
+		"sha256:id2": "sha256:id2",
+	}
+
+	var tests = []struct {
+		description    string
+		push           bool
+		tags           tag.ImageTags
+		remoteImages   map[string]bool
+		localImages    map[string]string
+		deleted        []string
+		expectedBuilt  []string
+		expectedSecond []build.Artifact
+	}{
+		{
+			description:   "local image still present",
+			localImages:   localImages,
+			expectedBuilt: []string{"image1", "image2"},
+			expectedSecond: []build.Artifact{
+				{ImageName: "image1", Tag: "image1:id1"},
+				{ImageName: "image2", Tag: "image2:id2"},
+			},
+		},
+		{
+			description:   "local image was deleted",
+			localImages:   localImages,
+			deleted:       []string{"sha256:id2"},
+			expectedBuilt: []string{"image1", "image2", "image2"},
+			expectedSecond: []build.Artifact{
+				{ImageName: "image1", Tag: "image1:id1"},
+				{ImageName: "image2", Tag: "image2:tag"},
+			},
+		},
+		{
+			description:   "remote image still present",
+			push:          true,
+			remoteImages:  map[string]bool{"image1@sha256:image1": true, "image2@sha256:image2": true},
+			expectedBuilt: []string{"image1", "image2"},
+			expectedSecond: []build.Artifact{
+				{ImageName: "image1", Tag: "image1:tag@sha256:image1"},
+				{ImageName: "image2", Tag: "image2:tag@sha256:image2"},
+			},
+		},
+		{
+			description:   "remote image was deleted",
+			push:          true,
+			remoteImages:  map[string]bool{"image2@sha256:image2": true},
+			expectedBuilt: []string{"image1", "image2", "image1"},
+			expectedSecond: []build.Artifact{
+				{ImageName: "image1", Tag: "image1:tag@sha256:image1"},
+				{ImageName: "image2", Tag: "image2:tag@sha256:image2"},
+			},
+		},
+		{
+			description:   "remote image with default repo",
+			push:          true,
+			tags:          tag.ImageTags{"image1": "gcr.io/repo/image1:tag", "image2": "gcr.io/repo/image2:tag"},
+			remoteImages:  map[string]bool{"gcr.io/repo/image1@sha256:image1": true, "gcr.io/repo/image2@sha256:image2": true},
+			expectedBuilt: []string{"image1", "image2"},
+			expectedSecond: []build.Artifact{
+				{ImageName: "image1", Tag: "gcr.io/repo/image1:tag@sha256:image1"},
+				{ImageName: "image2", Tag: "gcr.io/repo/image2:tag@sha256:image2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			remoteDigest = func(ref string) (string, error) {
+				if !test.remoteImages[ref] {
+					return "", errors.New("not found")
+				}
+				return "", nil
+			}
+			addTag = func(string, string) error { return nil }
+
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+			tmpDir.Write("Dockerfile", "FROM busybox")
+
+			api := &testutil.FakeAPIClient{TagToImageID: map[string]string{}}
+			for k, v := range test.localImages {
+				api.TagToImageID[k] = v
+			}
+			builder := &fakeBuilder{
+				dependencies: []string{tmpDir.Path("Dockerfile")},
+				push:         test.push,
+			}
+			artifacts := []*latest.Artifact{
+				{ImageName: "image1", Workspace: tmpDir.Root()},
+				{ImageName: "image2", Workspace: tmpDir.Root()},
+			}
+			cache := &Builder{
+				Builder:       builder,
+				artifactCache: ArtifactCache{},
+				cacheFile:     tmpDir.Path("cache"),
+				localDocker:   docker.NewLocalDaemon(api, nil),
+				inputs:        tag.NewInputDigestTagger(artifacts, builder.DependenciesForArtifact),
+			}
+			tags := test.tags
+			if tags == nil {
+				tags = tag.ImageTags{"image1": "image1:tag", "image2": "image2:tag"}
+			}
+
+			_, err := cache.Build(context.Background(), ioutil.Discard, tags, artifacts)
+			testutil.CheckError(t, false, err)
+
+			// The cache must have been persisted.
+			persisted, err := retrieveArtifactCache(cache.cacheFile)
+			testutil.CheckErrorAndDeepEqual(t, false, err, cache.artifactCache, persisted)
+
+			for _, ref := range test.deleted {
+				delete(api.TagToImageID, ref)
+			}
+
+			bRes, err := cache.Build(context.Background(), ioutil.Discard, tags, artifacts)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedSecond, bRes)
+			testutil.CheckDeepEqual(t, test.expectedBuilt, builder.built)
+		})
+	}
+}
+
+func TestRetrieveArtifactCache(t *testing.T) {
+	cacheFile, teardown := testutil.TempFile(t, "cache", []byte("hash:\n  digest: sha256:digest\n  id: sha256:id\n"))
+	defer teardown()
+
+	cache, err := retrieveArtifactCache(cacheFile)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, ArtifactCache{
+		"hash": {Digest: "sha256:digest", ID: "sha256:id"},
+	}, cache)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// sortArtifacts orders the artifacts so that each artifact comes after the
+// artifacts it requires. Requirements that are not part of the list are ignored.
+// The original order is kept as much as possible.
+func sortArtifacts(artifacts []*latest.Artifact) ([]*latest.Artifact, error) {
+	inList := map[string]bool{}
+	for _, a := range artifacts {
+		inList[a.ImageName] = true
+	}
+
+	var sorted []*latest.Artifact
+	done := map[string]bool{}
+
+	for len(sorted) < len(artifacts) {
+		progress := false
+
+		for _, a := range artifacts {
+			if done[a.ImageName] || !requirementsDone(a, inList, done) {
+				continue
+			}
+
+			sorted = append(sorted, a)
+			done[a.ImageName] = true
+			progress = true
+		}
+
+		if !progress {
+			var cycle []string
+			for _, a := range artifacts {
+				if !done[a.ImageName] {
+					cycle = append(cycle, a.ImageName)
+				}
+			}
+			return nil, fmt.Errorf("cycle detected between artifacts: %s", strings.Join(cycle, ", "))
+		}
+	}
+
+	return sorted, nil
+}
+
+func requirementsDone(a *latest.Artifact, inList, done map[string]bool) bool {
+	for _, r := range a.Requires {
+		if inList[r.ImageName] && !done[r.ImageName] {
+			return false
+		}
+	}
+	return true
+}
+
+// imagesByName initializes the fully qualified names of images, to be used by artifacts
+// that require them, with the tags. Those are replaced with the result of each build.
+func imagesByName(tags tag.ImageTags) map[string]string {
+	images := make(map[string]string, len(tags))
+	for imageName, tag := range tags {
+		images[imageName] = tag
+	}
+	return images
+}
+
+// withRequiredImages returns a copy of the artifact with the fully qualified
+// names of the required images passed as build args.
+func withRequiredImages(a *latest.Artifact, images map[string]string) (*latest.Artifact, error) {
+	if a.DockerArtifact == nil || !hasAlias(a) {
+		return a, nil
+	}
+
+	buildArgs := map[string]*string{}
+	for k, v := range a.DockerArtifact.BuildArgs {
+		buildArgs[k] = v
+	}
+
+	for _, r := range a.Requires {
+		if r.Alias == "" {
+			continue
+		}
+
+		image, present := images[r.ImageName]
+		if !present {
+			return nil, fmt.Errorf("unable to find the image built for %s, required by %s", r.ImageName, a.ImageName)
+		}
+		buildArgs[r.Alias] = &image
+	}
+
+	dockerArtifact := *a.DockerArtifact
+	dockerArtifact.BuildArgs = buildArgs
+
+	withImages := *a
+	withImages.DockerArtifact = &dockerArtifact
+
+	return &withImages, nil
+}
+
+func hasAlias(a *latest.Artifact) bool {
+	for _, r := range a.Requires {
+		if r.Alias != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func requiring(imageName string, requires ...string) *latest.Artifact {
+	a := &latest.Artifact{
+		ImageName: imageName,
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{},
+		},
+	}
+	for _, r := range requires {
+		a.Requires = append(a.Requires, &latest.ArtifactDependency{
+			ImageName: r,
+			Alias:     "IMAGE_" + r,
+		})
+	}
+	return a
+}
+
+func imageNames(artifacts []*latest.Artifact) []string {
+	var names []string
+	for _, a := range artifacts {
+		names = append(names, a.ImageName)
+	}
+	return names
+}
+
+func TestSortArtifacts(t *testing.T) {
+	var tests = []struct {
+		description string
+		artifacts   []*latest.Artifact
+		expected    []string
+		shouldErr   bool
+	}{
+		{
+			description: "no requirements",
+			artifacts:   []*latest.Artifact{requiring("a"), requiring("b")},
+			expected:    []string{"a", "b"},
+		},
+		{
+			description: "requirement comes first",
+			artifacts:   []*latest.Artifact{requiring("a", "b"), requiring("b")},
+			expected:    []string{"b", "a"},
+		},
+		{
+			description: "diamond",
+			artifacts:   []*latest.Artifact{requiring("a", "b", "c"), requiring("b", "d"), requiring("c", "d"), requiring("d")},
+			expected:    []string{"d", "b", "c", "a"},
+		},
+		{
+			description: "requirement not in the list",
+			artifacts:   []*latest.Artifact{requiring("a", "other"), requiring("b")},
+			expected:    []string{"a", "b"},
+		},
+		{
+			description: "cycle",
+			artifacts:   []*latest.Artifact{requiring("a", "b"), requiring("b", "a"), requiring("c")},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			sorted, err := sortArtifacts(test.artifacts)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, imageNames(sorted))
+		})
+	}
+}
+
+func TestWithRequiredImages(t *testing.T) {
+	arg := "value"
+	artifact := requiring("a", "b")
+	artifact.DockerArtifact.BuildArgs = map[string]*string{"ARG": &arg}
+
+	withImages, err := withRequiredImages(artifact, map[string]string{"b": "b:tag@sha256:digest"})
+	testutil.CheckError(t, false, err)
+
+	testutil.CheckDeepEqual(t, "value", *withImages.DockerArtifact.BuildArgs["ARG"])
+	testutil.CheckDeepEqual(t, "b:tag@sha256:digest", *withImages.DockerArtifact.BuildArgs["IMAGE_b"])
+	// The original artifact is left untouched.
+	testutil.CheckDeepEqual(t, 1, len(artifact.DockerArtifact.BuildArgs))
+
+	_, err = withRequiredImages(artifact, map[string]string{})
+	testutil.CheckError(t, true, err)
+}
+
+func TestBuildWithRequirements(t *testing.T) {
+	artifacts := []*latest.Artifact{
+		requiring("app", "base"),
+		requiring("base"),
+		requiring("other", "external"),
+	}
+	tags := tag.ImageTags{
+		"app":      "app:tag",
+		"base":     "base:tag",
+		"other":    "other:tag",
+		"external": "external:previous",
+	}
+	expected := []Artifact{
+		{ImageName: "base", Tag: "base:tag@sha256:digest"},
+		{ImageName: "other", Tag: "other:tag@sha256:digest"},
+		{ImageName: "app", Tag: "app:tag@sha256:digest"},
+	}
+
+	for name, builder := range map[string]func(context.Context, io.Writer, tag.ImageTags, []*latest.Artifact, artifactBuilder) ([]Artifact, error){
+		"sequence": InSequence,
+		"parallel": func(ctx context.Context, out io.Writer, tags tag.ImageTags, artifacts []*latest.Artifact, buildArtifact artifactBuilder) ([]Artifact, error) {
+			return InParallel(ctx, out, tags, artifacts, buildArtifact, 0, true)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			buildArtifact := func(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
+				for _, r := range artifact.Requires {
+					fmt.Fprintf(out, "%s=%s\n", r.Alias, *artifact.DockerArtifact.BuildArgs[r.Alias])
+				}
+				return tag + "@sha256:digest", nil
+			}
+
+			out := new(bytes.Buffer)
+			got, err := builder(context.Background(), out, tags, artifacts, buildArtifact)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, expected, got)
+			testutil.CheckDeepEqual(t, "Building [base]...\n"+
+				"Building [other]...\n"+
+				"IMAGE_external=external:previous\n"+
+				"Building [app]...\n"+
+				"IMAGE_base=base:tag@sha256:digest\n", out.String())
+		})
+	}
+}
+
+func TestBuildRequirementFails(t *testing.T) {
+	artifacts := []*latest.Artifact{requiring("app", "base"), requiring("base")}
+	tags := tag.ImageTags{"app": "app:tag", "base": "base:tag"}
+
+	var built []string
+	buildArtifact := func(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
+		built = append(built, artifact.ImageName)
+		return "", fmt.Errorf("build fails")
+	}
+
+	_, err := InParallel(context.Background(), new(bytes.Buffer), tags, artifacts, buildArtifact, 0, false)
+
+	testutil.CheckErrorAndDeepEqual(t, true, err, []string{"base"}, built)
+}
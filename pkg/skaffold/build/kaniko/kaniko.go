@@ -22,34 +22,103 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/pkg/errors"
 )
 
 // Build builds a list of artifacts with Kaniko.
 func (b *Builder) Build(ctx context.Context, out io.Writer, tags tag.ImageTags, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	if b.warmPod != nil {
+		if err := b.warmPod.start(ctx, out, b); err != nil {
+			return nil, errors.Wrap(err, "starting kaniko pod")
+		}
+	} else {
+		teardownSecrets, err := b.setupSecrets(out)
+		if err != nil {
+			return nil, err
+		}
+		defer teardownSecrets()
+	}
+
+	concurrency := 0
+	if b.Concurrency != nil {
+		concurrency = *b.Concurrency
+	}
+	failFast := b.FailFast == nil || *b.FailFast
+
+	return build.InParallel(ctx, out, tags, artifacts, b.buildArtifactWithKaniko, concurrency, failFast)
+}
+
+// Stop deletes the Kaniko pod kept running between builds, if any.
+func (b *Builder) Stop(ctx context.Context) error {
+	if b.warmPod == nil {
+		return nil
+	}
+
+	return b.warmPod.stop()
+}
+
+// setupSecrets creates the secrets mounted into the Kaniko pod.
+func (b *Builder) setupSecrets(out io.Writer) (func(), error) {
 	teardownPullSecret, err := b.setupPullSecret(out)
 	if err != nil {
 		return nil, errors.Wrap(err, "setting up pull secret")
 	}
-	defer teardownPullSecret()
 
-	if b.DockerConfig != nil {
-		teardownDockerConfigSecret, err := b.setupDockerConfigSecret(out)
-		if err != nil {
-			return nil, errors.Wrap(err, "setting up docker config secret")
-		}
-		defer teardownDockerConfigSecret()
+	if b.DockerConfig == nil {
+		return teardownPullSecret, nil
+	}
+
+	teardownDockerConfigSecret, err := b.setupDockerConfigSecret(out)
+	if err != nil {
+		teardownPullSecret()
+		return nil, errors.Wrap(err, "setting up docker config secret")
 	}
 
-	return build.InParallel(ctx, out, tags, artifacts, b.buildArtifactWithKaniko)
+	return func() {
+		teardownDockerConfigSecret()
+		teardownPullSecret()
+	}, nil
 }
 
 func (b *Builder) buildArtifactWithKaniko(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
-	digest, err := b.run(ctx, out, artifact, tag)
+	var (
+		digest string
+		err    error
+	)
+	if len(artifact.Platforms) > 0 {
+		digest, err = b.runPlatforms(ctx, out, artifact, tag)
+	} else {
+		digest, err = b.run(ctx, out, artifact, tag, "")
+	}
 	if err != nil {
 		return "", errors.Wrapf(err, "kaniko build for [%s]", artifact.ImageName)
 	}
 
 	return tag + "@" + digest, nil
 }
+
+// runPlatforms builds an image for each platform of an artifact and then
+// pushes a manifest list that references all those images.
+// It returns the digest of the manifest list.
+func (b *Builder) runPlatforms(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
+	var images []docker.PlatformImage
+	for _, platform := range artifact.Platforms {
+		platformTag, err := docker.PlatformTag(tag, platform)
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := b.run(ctx, out, artifact, platformTag, platform); err != nil {
+			return "", errors.Wrapf(err, "building for %s", platform)
+		}
+
+		images = append(images, docker.PlatformImage{
+			Platform: platform,
+			Tag:      platformTag,
+		})
+	}
+
+	return docker.CreateManifestList(tag, images)
+}
@@ -31,13 +31,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
+func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag, platform string) (string, error) {
 	if artifact.DockerArtifact == nil {
 		return "", errors.New("kaniko builder supports only Docker artifacts")
 	}
 
+	if b.warmPod != nil {
+		return b.warmPod.run(ctx, out, b, artifact, tag, platform)
+	}
+
 	// Prepare context
-	s := sources.Retrieve(b.KanikoBuild)
+	s := sources.Retrieve(b.KanikoBuild, b.kubeContext)
 	dependencies, err := b.DependenciesForArtifact(ctx, artifact)
 	if err != nil {
 		return "", errors.Wrapf(err, "getting dependencies for %s", artifact.ImageName)
@@ -49,22 +53,7 @@ func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artif
 	defer s.Cleanup(ctx)
 
 	// Create pod spec
-	args := []string{
-		"--dockerfile", artifact.DockerArtifact.DockerfilePath,
-		"--context", context,
-		"--destination", tag,
-		"-v", logLevel().String()}
-	args = append(args, b.AdditionalFlags...)
-	args = append(args, docker.GetBuildArgs(artifact.DockerArtifact)...)
-
-	if b.Cache != nil {
-		args = append(args, "--cache=true")
-		if b.Cache.Repo != "" {
-			args = append(args, fmt.Sprintf("--cache-repo=%s", b.Cache.Repo))
-		}
-	}
-
-	podSpec := s.Pod(args)
+	podSpec := s.Pod(b.args(artifact, context, tag, platform))
 
 	// Create pod
 	client, err := kubernetes.GetClientset()
@@ -99,3 +88,26 @@ func (b *Builder) run(ctx context.Context, out io.Writer, artifact *latest.Artif
 
 	return docker.RemoteDigest(tag)
 }
+
+// args returns the arguments of the Kaniko executor.
+func (b *Builder) args(artifact *latest.Artifact, context, tag, platform string) []string {
+	args := []string{
+		"--dockerfile", artifact.DockerArtifact.DockerfilePath,
+		"--context", context,
+		"--destination", tag,
+		"-v", logLevel().String()}
+	args = append(args, b.AdditionalFlags...)
+	args = append(args, docker.GetBuildArgs(artifact.DockerArtifact)...)
+	if platform != "" {
+		args = append(args, fmt.Sprintf("--customPlatform=%s", platform))
+	}
+
+	if b.Cache != nil {
+		args = append(args, "--cache=true")
+		if b.Cache.Repo != "" {
+			args = append(args, fmt.Sprintf("--cache-repo=%s", b.Cache.Repo))
+		}
+	}
+
+	return args
+}
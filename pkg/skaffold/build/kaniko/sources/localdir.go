@@ -42,8 +42,9 @@ const (
 // LocalDir refers to kaniko using a local directory as a buildcontext
 // skaffold copies the buildcontext into the local directory via kubectl cp
 type LocalDir struct {
-	cfg     *latest.KanikoBuild
-	tarPath string
+	cfg         *latest.KanikoBuild
+	kubeContext string
+	tarPath     string
 }
 
 // Setup for LocalDir creates a tarball of the buildcontext and stores it in /tmp
@@ -103,21 +104,64 @@ func (g *LocalDir) ModifyPod(ctx context.Context, p *v1.Pod) error {
 		return errors.Wrap(err, "waiting for pod to initialize")
 	}
 
+	// Copy the context to the empty dir and extract it
+	if err := g.CopyToPod(ctx, p, initContainer, constants.DefaultKanikoEmptyDirMountPath); err != nil {
+		return err
+	}
+	// Generate a file to successfully terminate the init container
+	file := exec.CommandContext(ctx, "kubectl", "--context", g.kubeContext, "exec", p.Name, "-c", initContainer, "-n", p.Namespace, "--", "touch", "/tmp/complete")
+	return util.RunCmd(file)
+}
+
+// CopyToPod copies the buildcontext tarball into a container of a pod,
+// via kubectl exec, and extracts it into the given directory.
+func (g *LocalDir) CopyToPod(ctx context.Context, p *v1.Pod, container, dir string) error {
 	f, err := os.Open(g.tarPath)
 	if err != nil {
 		return errors.Wrap(err, "opening context tar")
 	}
 	defer f.Close()
 
-	// Copy the context to the empty dir and extract it
-	copyAndExtract := exec.CommandContext(ctx, "kubectl", "exec", "-i", p.Name, "-c", initContainer, "-n", p.Namespace, "--", "tar", "-xzf", "-", "-C", constants.DefaultKanikoEmptyDirMountPath)
+	copyAndExtract := exec.CommandContext(ctx, "kubectl", "--context", g.kubeContext, "exec", "-i", p.Name, "-c", container, "-n", p.Namespace, "--", "sh", "-c", fmt.Sprintf("mkdir -p %s && tar -xzf - -C %s", dir, dir))
 	copyAndExtract.Stdin = f
 	if err := util.RunCmd(copyAndExtract); err != nil {
 		return errors.Wrap(err, "copying and extracting buildcontext to empty dir")
 	}
-	// Generate a file to successfully terminate the init container
-	file := exec.CommandContext(ctx, "kubectl", "exec", p.Name, "-c", initContainer, "-n", p.Namespace, "--", "touch", "/tmp/complete")
-	return util.RunCmd(file)
+
+	return nil
+}
+
+// WarmPod returns the spec of a long-lived Kaniko pod. Instead of running
+// a single build, its Kaniko container idles so that build contexts can be copied
+// into its empty dir and successive builds can be run with kubectl exec.
+// An additional empty dir holds the base images cached between builds.
+func WarmPod(cfg *latest.KanikoBuild) *v1.Pod {
+	p := podTemplate(cfg, nil)
+	p.GenerateName = "kaniko-warm-"
+
+	kaniko := &p.Spec.Containers[0]
+	kaniko.Command = []string{"sh", "-c", "while true; do sleep 3600; done"}
+	kaniko.VolumeMounts = append(kaniko.VolumeMounts,
+		v1.VolumeMount{
+			Name:      constants.DefaultKanikoEmptyDirName,
+			MountPath: constants.DefaultKanikoEmptyDirMountPath,
+		},
+		v1.VolumeMount{
+			Name:      constants.DefaultKanikoCacheDirName,
+			MountPath: constants.DefaultKanikoCacheDirMountPath,
+		},
+	)
+
+	for _, name := range []string{constants.DefaultKanikoEmptyDirName, constants.DefaultKanikoCacheDirName} {
+		p.Spec.Volumes = append(p.Spec.Volumes, v1.Volume{
+			Name: name,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	return p
 }
 
 // Cleanup deletes the buildcontext tarball stored on the local filesystem
@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// applyOverrides merges the user provided pod template into the Kaniko pod.
+// Values are expected to have been validated beforehand.
+func applyOverrides(pod *v1.Pod, t *latest.KanikoPodTemplate) {
+	if t == nil {
+		return
+	}
+
+	kaniko := &pod.Spec.Containers[0]
+
+	if len(t.Annotations) > 0 && pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	for k, v := range t.Annotations {
+		pod.Annotations[k] = v
+	}
+
+	if t.Resources != nil {
+		kaniko.Resources = v1.ResourceRequirements{
+			Requests: resourceList(t.Resources.Requests),
+			Limits:   resourceList(t.Resources.Limits),
+		}
+	}
+
+	if len(t.NodeSelector) > 0 && pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = map[string]string{}
+	}
+	for k, v := range t.NodeSelector {
+		pod.Spec.NodeSelector[k] = v
+	}
+
+	for _, toleration := range t.Tolerations {
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, v1.Toleration{
+			Key:               toleration.Key,
+			Operator:          v1.TolerationOperator(toleration.Operator),
+			Value:             toleration.Value,
+			Effect:            v1.TaintEffect(toleration.Effect),
+			TolerationSeconds: toleration.TolerationSeconds,
+		})
+	}
+
+	if t.ServiceAccountName != "" {
+		pod.Spec.ServiceAccountName = t.ServiceAccountName
+	}
+
+	for _, env := range t.Env {
+		kv := strings.SplitN(env, "=", 2)
+		kaniko.Env = setEnv(kaniko.Env, kv[0], kv[len(kv)-1])
+	}
+
+	for _, volume := range t.Volumes {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name:         volume.Name,
+			VolumeSource: volumeSource(volume),
+		})
+	}
+
+	for _, mount := range t.VolumeMounts {
+		kaniko.VolumeMounts = append(kaniko.VolumeMounts, v1.VolumeMount{
+			Name:      mount.Name,
+			MountPath: mount.MountPath,
+			ReadOnly:  mount.ReadOnly,
+		})
+	}
+}
+
+// setEnv sets an environment variable, replacing any previous value.
+func setEnv(env []v1.EnvVar, name, value string) []v1.EnvVar {
+	for i := range env {
+		if env[i].Name == name {
+			env[i].Value = value
+			return env
+		}
+	}
+
+	return append(env, v1.EnvVar{Name: name, Value: value})
+}
+
+func resourceList(l *latest.ResourceList) v1.ResourceList {
+	if l == nil {
+		return nil
+	}
+
+	list := v1.ResourceList{}
+	for name, value := range map[v1.ResourceName]string{
+		v1.ResourceCPU:              l.CPU,
+		v1.ResourceMemory:           l.Memory,
+		v1.ResourceEphemeralStorage: l.EphemeralStorage,
+	} {
+		if q, err := resource.ParseQuantity(value); value != "" && err == nil {
+			list[name] = q
+		}
+	}
+
+	return list
+}
+
+func volumeSource(v *latest.KanikoVolume) v1.VolumeSource {
+	switch {
+	case v.Secret != "":
+		return v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: v.Secret}}
+	case v.ConfigMap != "":
+		return v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: v.ConfigMap}}}
+	case v.HostPath != "":
+		return v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: v.HostPath}}
+	case v.PersistentVolumeClaim != "":
+		return v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: v.PersistentVolumeClaim}}
+	default:
+		return v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}
+	}
+}
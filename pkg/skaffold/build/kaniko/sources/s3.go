@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sources"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// For testing
+var randomID = util.RandomFourCharacterID
+
+// S3Bucket refers to kaniko downloading its buildcontext from an S3-compatible bucket
+type S3Bucket struct {
+	cfg *latest.KanikoBuild
+
+	// credsSecret is the temporary secret holding the local credentials,
+	// when the bucket has no `secretName`.
+	credsSecret string
+}
+
+// Setup uploads the context to the provided S3 bucket, unless it's already there
+func (s *S3Bucket) Setup(ctx context.Context, out io.Writer, artifact *latest.Artifact, initialTag string, dependencies []string) (string, error) {
+	bucket := s.cfg.BuildContext.S3Bucket
+
+	creds, err := sources.S3CredentialsFromEnv()
+	if err != nil {
+		return "", errors.Wrap(err, "getting S3 credentials")
+	}
+
+	color.Default.Fprintln(out, "Uploading sources to", bucket.Bucket, "S3 bucket")
+
+	objectName, err := sources.UploadToS3(ctx, artifact, bucket, creds, dependencies)
+	if err != nil {
+		return "", errors.Wrap(err, "uploading sources to S3")
+	}
+
+	if bucket.SecretName == "" {
+		if err := s.createCredsSecret(out, creds); err != nil {
+			return "", err
+		}
+	}
+
+	context := fmt.Sprintf("s3://%s/%s", bucket.Bucket, objectName)
+	return context, nil
+}
+
+// Pod returns the pod template for this builder, configured to access the bucket
+func (s *S3Bucket) Pod(args []string) *v1.Pod {
+	bucket := s.cfg.BuildContext.S3Bucket
+
+	p := podTemplate(s.cfg, args)
+	kaniko := &p.Spec.Containers[0]
+
+	kaniko.Env = append(kaniko.Env,
+		v1.EnvVar{Name: "AWS_REGION", Value: bucket.Region},
+		v1.EnvVar{Name: "S3_FORCE_PATH_STYLE", Value: strconv.FormatBool(bucket.PathStyle)},
+	)
+	if bucket.Endpoint != "" {
+		kaniko.Env = append(kaniko.Env, v1.EnvVar{Name: "S3_ENDPOINT", Value: bucket.Endpoint})
+	}
+
+	secretName := bucket.SecretName
+	if secretName == "" {
+		secretName = s.credsSecret
+	}
+	if secretName != "" {
+		kaniko.EnvFrom = append(kaniko.EnvFrom, v1.EnvFromSource{
+			SecretRef: &v1.SecretEnvSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: secretName},
+			},
+		})
+	}
+
+	return p
+}
+
+// ModifyPod does nothing here, since we just need to let kaniko run to completion
+func (s *S3Bucket) ModifyPod(ctx context.Context, p *v1.Pod) error {
+	return nil
+}
+
+// Cleanup deletes the temporary credentials secret. Since objects are named
+// after their content, they are kept to avoid uploading identical sources again.
+func (s *S3Bucket) Cleanup(ctx context.Context) error {
+	if s.credsSecret == "" {
+		return nil
+	}
+
+	client, err := kubernetes.Client()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	if err := client.CoreV1().Secrets(s.cfg.Namespace).Delete(s.credsSecret, &metav1.DeleteOptions{}); err != nil {
+		return errors.Wrapf(err, "deleting S3 credentials secret %s", s.credsSecret)
+	}
+	s.credsSecret = ""
+
+	return nil
+}
+
+// createCredsSecret stores the local credentials in a temporary secret,
+// so that they are not visible in the pod's spec.
+func (s *S3Bucket) createCredsSecret(out io.Writer, creds *sources.S3Credentials) error {
+	name := "skaffold-s3-credentials-" + randomID()
+	color.Default.Fprintf(out, "Creating S3 credentials secret [%s]...\n", name)
+
+	client, err := kubernetes.Client()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	data := map[string][]byte{
+		"AWS_ACCESS_KEY_ID":     []byte(creds.AccessKeyID),
+		"AWS_SECRET_ACCESS_KEY": []byte(creds.SecretAccessKey),
+	}
+	if creds.SessionToken != "" {
+		data["AWS_SESSION_TOKEN"] = []byte(creds.SessionToken)
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"skaffold-kaniko": "skaffold-kaniko"},
+		},
+		Data: data,
+	}
+
+	if _, err := client.CoreV1().Secrets(s.cfg.Namespace).Create(secret); err != nil {
+		return errors.Wrap(err, "creating S3 credentials secret")
+	}
+	logrus.Debugf("Created S3 credentials secret %s", name)
+	s.credsSecret = name
+
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	pkgkubernetes "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sources"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestS3BucketPod(t *testing.T) {
+	var tests = []struct {
+		description     string
+		bucket          *latest.S3Bucket
+		credsSecret     string
+		expectedEnv     []v1.EnvVar
+		expectedEnvFrom []v1.EnvFromSource
+	}{
+		{
+			description: "minio with a secret",
+			bucket:      &latest.S3Bucket{Bucket: "bucket", Endpoint: "http://minio:9000", Region: "us-east-1", PathStyle: true, SecretName: "minio"},
+			expectedEnv: []v1.EnvVar{
+				{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: "/secret/kaniko-secret"},
+				{Name: "AWS_REGION", Value: "us-east-1"},
+				{Name: "S3_FORCE_PATH_STYLE", Value: "true"},
+				{Name: "S3_ENDPOINT", Value: "http://minio:9000"},
+			},
+			expectedEnvFrom: []v1.EnvFromSource{{
+				SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "minio"}},
+			}},
+		},
+		{
+			description: "aws with local credentials",
+			bucket:      &latest.S3Bucket{Bucket: "bucket", Region: "eu-west-1"},
+			credsSecret: "skaffold-s3-credentials-abcd",
+			expectedEnv: []v1.EnvVar{
+				{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: "/secret/kaniko-secret"},
+				{Name: "AWS_REGION", Value: "eu-west-1"},
+				{Name: "S3_FORCE_PATH_STYLE", Value: "false"},
+			},
+			expectedEnvFrom: []v1.EnvFromSource{{
+				SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "skaffold-s3-credentials-abcd"}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			s := &S3Bucket{
+				cfg: &latest.KanikoBuild{
+					BuildContext: &latest.KanikoBuildContext{S3Bucket: test.bucket},
+				},
+				credsSecret: test.credsSecret,
+			}
+
+			pod := s.Pod([]string{"--context", "s3://bucket/context.tar.gz"})
+
+			testutil.CheckDeepEqual(t, test.expectedEnv, pod.Spec.Containers[0].Env)
+			testutil.CheckDeepEqual(t, test.expectedEnvFrom, pod.Spec.Containers[0].EnvFrom)
+		})
+	}
+}
+
+func TestS3BucketCredsSecret(t *testing.T) {
+	defer func(r func() string) { randomID = r }(randomID)
+	randomID = func() string { return "abcd" }
+
+	client := fake.NewSimpleClientset()
+	defer func(c func() (kubernetes.Interface, error)) { pkgkubernetes.Client = c }(pkgkubernetes.Client)
+	pkgkubernetes.Client = func() (kubernetes.Interface, error) { return client, nil }
+
+	s := &S3Bucket{cfg: &latest.KanikoBuild{Namespace: "ns"}}
+
+	err := s.createCredsSecret(ioutil.Discard, &sources.S3Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET", SessionToken: "TOKEN"})
+	testutil.CheckError(t, false, err)
+
+	secret, err := client.CoreV1().Secrets("ns").Get("skaffold-s3-credentials-abcd", metav1.GetOptions{})
+	testutil.CheckErrorAndDeepEqual(t, false, err, map[string][]byte{
+		"AWS_ACCESS_KEY_ID":     []byte("AKID"),
+		"AWS_SECRET_ACCESS_KEY": []byte("SECRET"),
+		"AWS_SESSION_TOKEN":     []byte("TOKEN"),
+	}, secret.Data)
+
+	err = s.Cleanup(context.Background())
+	testutil.CheckError(t, false, err)
+
+	_, err = client.CoreV1().Secrets("ns").Get("skaffold-s3-credentials-abcd", metav1.GetOptions{})
+	testutil.CheckError(t, true, err)
+}
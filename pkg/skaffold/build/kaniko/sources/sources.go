@@ -35,9 +35,23 @@ type BuildContextSource interface {
 }
 
 // Retrieve returns the correct build context based on the config
-func Retrieve(cfg *latest.KanikoBuild) BuildContextSource {
+func Retrieve(cfg *latest.KanikoBuild, kubeContext string) BuildContextSource {
 	if cfg.BuildContext.LocalDir != nil {
 		return &LocalDir{
+			cfg:         cfg,
+			kubeContext: kubeContext,
+		}
+	}
+
+	if cfg.BuildContext.Stdin != nil {
+		return &Stdin{
+			cfg:         cfg,
+			kubeContext: kubeContext,
+		}
+	}
+
+	if cfg.BuildContext.S3Bucket != nil {
+		return &S3Bucket{
 			cfg: cfg,
 		}
 	}
@@ -86,10 +100,16 @@ func podTemplate(cfg *latest.KanikoBuild, args []string) *v1.Pod {
 		},
 	}
 
-	if cfg.DockerConfig == nil {
-		return pod
+	if cfg.DockerConfig != nil {
+		addDockerConfig(pod, cfg.DockerConfig)
 	}
 
+	applyOverrides(pod, cfg.PodTemplate)
+
+	return pod
+}
+
+func addDockerConfig(pod *v1.Pod, dockerConfig *latest.DockerConfig) {
 	volumeMount := v1.VolumeMount{
 		Name:      constants.DefaultKanikoDockerConfigSecretName,
 		MountPath: constants.DefaultKanikoDockerConfigPath,
@@ -101,12 +121,10 @@ func podTemplate(cfg *latest.KanikoBuild, args []string) *v1.Pod {
 		Name: constants.DefaultKanikoDockerConfigSecretName,
 		VolumeSource: v1.VolumeSource{
 			Secret: &v1.SecretVolumeSource{
-				SecretName: cfg.DockerConfig.SecretName,
+				SecretName: dockerConfig.SecretName,
 			},
 		},
 	}
 
 	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
-
-	return pod
 }
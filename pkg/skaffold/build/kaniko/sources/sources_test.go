@@ -0,0 +1,218 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodTemplate(t *testing.T) {
+	tolerationSeconds := int64(60)
+
+	var tests = []struct {
+		description       string
+		cfg               *latest.KanikoBuild
+		expected          *v1.Pod
+		expectedResources map[string]string
+	}{
+		{
+			description: "defaults",
+			cfg: &latest.KanikoBuild{
+				Image:          "kaniko",
+				Namespace:      "ns",
+				PullSecretName: "secret",
+			},
+			expected: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "kaniko-",
+					Labels:       map[string]string{"skaffold-kaniko": "skaffold-kaniko"},
+					Namespace:    "ns",
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name:            "kaniko",
+						Image:           "kaniko",
+						Args:            []string{"--destination", "image"},
+						ImagePullPolicy: v1.PullIfNotPresent,
+						Env:             []v1.EnvVar{{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: "/secret/kaniko-secret"}},
+						VolumeMounts:    []v1.VolumeMount{{Name: "kaniko-secret", MountPath: "/secret"}},
+					}},
+					RestartPolicy: v1.RestartPolicyNever,
+					Volumes: []v1.Volume{{
+						Name:         "kaniko-secret",
+						VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "secret"}},
+					}},
+				},
+			},
+		},
+		{
+			description: "overrides",
+			cfg: &latest.KanikoBuild{
+				Image:          "kaniko",
+				Namespace:      "ns",
+				PullSecretName: "secret",
+				PodTemplate: &latest.KanikoPodTemplate{
+					Annotations: map[string]string{"key": "value"},
+					Resources: &latest.ResourceRequirements{
+						Requests: &latest.ResourceList{CPU: "500m", Memory: "1Gi"},
+						Limits:   &latest.ResourceList{EphemeralStorage: "10Gi"},
+					},
+					NodeSelector:       map[string]string{"pool": "builds"},
+					Tolerations:        []*latest.Toleration{{Key: "builds", Operator: "Exists", Effect: "NoExecute", TolerationSeconds: &tolerationSeconds}},
+					ServiceAccountName: "builder",
+					Env:                []string{"GOOGLE_APPLICATION_CREDENTIALS=/other", "HTTP_PROXY=proxy:3128"},
+					Volumes: []*latest.KanikoVolume{
+						{Name: "certs", ConfigMap: "ca-certs"},
+						{Name: "scratch", EmptyDir: true},
+					},
+					VolumeMounts: []*latest.KanikoVolumeMount{{Name: "certs", MountPath: "/certs", ReadOnly: true}},
+				},
+			},
+			expectedResources: map[string]string{
+				"requests.cpu":             "500m",
+				"requests.memory":          "1Gi",
+				"limits.ephemeral-storage": "10Gi",
+			},
+			expected: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "kaniko-",
+					Labels:       map[string]string{"skaffold-kaniko": "skaffold-kaniko"},
+					Annotations:  map[string]string{"key": "value"},
+					Namespace:    "ns",
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name:            "kaniko",
+						Image:           "kaniko",
+						Args:            []string{"--destination", "image"},
+						ImagePullPolicy: v1.PullIfNotPresent,
+						Env: []v1.EnvVar{
+							{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: "/other"},
+							{Name: "HTTP_PROXY", Value: "proxy:3128"},
+						},
+						VolumeMounts: []v1.VolumeMount{
+							{Name: "kaniko-secret", MountPath: "/secret"},
+							{Name: "certs", MountPath: "/certs", ReadOnly: true},
+						},
+					}},
+					RestartPolicy:      v1.RestartPolicyNever,
+					NodeSelector:       map[string]string{"pool": "builds"},
+					ServiceAccountName: "builder",
+					Tolerations: []v1.Toleration{{
+						Key:               "builds",
+						Operator:          v1.TolerationOpExists,
+						Effect:            v1.TaintEffectNoExecute,
+						TolerationSeconds: &tolerationSeconds,
+					}},
+					Volumes: []v1.Volume{
+						{
+							Name:         "kaniko-secret",
+							VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "secret"}},
+						},
+						{
+							Name:         "certs",
+							VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "ca-certs"}}},
+						},
+						{
+							Name:         "scratch",
+							VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			pod := podTemplate(test.cfg, []string{"--destination", "image"})
+
+			// Quantities can't be compared with cmp
+			testutil.CheckDeepEqual(t, test.expectedResources, resourcesAsStrings(pod.Spec.Containers[0].Resources))
+			pod.Spec.Containers[0].Resources = v1.ResourceRequirements{}
+
+			testutil.CheckDeepEqual(t, test.expected, pod)
+		})
+	}
+}
+
+func resourcesAsStrings(r v1.ResourceRequirements) map[string]string {
+	var resources map[string]string
+
+	for prefix, list := range map[string]v1.ResourceList{"requests": r.Requests, "limits": r.Limits} {
+		for name, quantity := range list {
+			if resources == nil {
+				resources = map[string]string{}
+			}
+			resources[prefix+"."+string(name)] = quantity.String()
+		}
+	}
+
+	return resources
+}
+
+func TestWarmPod(t *testing.T) {
+	pod := WarmPod(&latest.KanikoBuild{
+		Image:          "kaniko:debug",
+		Namespace:      "ns",
+		PullSecretName: "secret",
+	})
+
+	expected := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kaniko-warm-",
+			Labels:       map[string]string{"skaffold-kaniko": "skaffold-kaniko"},
+			Namespace:    "ns",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:            "kaniko",
+				Image:           "kaniko:debug",
+				Command:         []string{"sh", "-c", "while true; do sleep 3600; done"},
+				ImagePullPolicy: v1.PullIfNotPresent,
+				Env:             []v1.EnvVar{{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: "/secret/kaniko-secret"}},
+				VolumeMounts: []v1.VolumeMount{
+					{Name: "kaniko-secret", MountPath: "/secret"},
+					{Name: "kaniko-emptydir", MountPath: "/kaniko/buildcontext"},
+					{Name: "kaniko-cache", MountPath: "/cache"},
+				},
+			}},
+			RestartPolicy: v1.RestartPolicyNever,
+			Volumes: []v1.Volume{
+				{
+					Name:         "kaniko-secret",
+					VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "secret"}},
+				},
+				{
+					Name:         "kaniko-emptydir",
+					VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+				},
+				{
+					Name:         "kaniko-cache",
+					VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+				},
+			},
+		},
+	}
+
+	testutil.CheckDeepEqual(t, expected, pod)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"context"
+	"io"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sources"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+)
+
+// Stdin refers to kaniko reading its buildcontext from its standard input.
+// skaffold streams the buildcontext to the kaniko container via kubectl attach.
+type Stdin struct {
+	cfg          *latest.KanikoBuild
+	kubeContext  string
+	artifact     *latest.Artifact
+	dependencies []string
+	attached     chan error
+}
+
+// Setup for Stdin only remembers what should be streamed once the pod is running
+func (s *Stdin) Setup(ctx context.Context, out io.Writer, artifact *latest.Artifact, initialTag string, dependencies []string) (string, error) {
+	color.Default.Fprintln(out, "Streaming build context to the kaniko pod")
+
+	s.artifact = artifact
+	s.dependencies = dependencies
+
+	return "tar://stdin", nil
+}
+
+// Pod returns the pod template with a kaniko container that reads from stdin
+func (s *Stdin) Pod(args []string) *v1.Pod {
+	p := podTemplate(s.cfg, args)
+	p.Spec.Containers[0].Stdin = true
+	p.Spec.Containers[0].StdinOnce = true
+	return p
+}
+
+// ModifyPod waits for the kaniko container to run and streams the buildcontext to it
+func (s *Stdin) ModifyPod(ctx context.Context, p *v1.Pod) error {
+	client, err := kubernetes.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "getting clientset")
+	}
+	if err := kubernetes.WaitForPodReady(ctx, client.CoreV1().Pods(p.Namespace), p.Name); err != nil {
+		return errors.Wrap(err, "waiting for pod to run")
+	}
+
+	return s.stream(ctx, p)
+}
+
+// stream writes the buildcontext tarball to the stdin of kubectl attach.
+// It returns once the whole tarball is sent. kubectl attach keeps running
+// until kaniko exits.
+func (s *Stdin) stream(ctx context.Context, p *v1.Pod) error {
+	r, w := io.Pipe()
+
+	attach := exec.CommandContext(ctx, "kubectl", "--context", s.kubeContext, "attach", "-i", p.Name, "-c", constants.DefaultKanikoContainerName, "-n", p.Namespace)
+	attach.Stdin = r
+
+	s.attached = make(chan error, 1)
+	go func() {
+		err := util.RunCmd(attach)
+		// Unblock the writer if kubectl stops reading
+		r.CloseWithError(errors.New("kubectl attach exited"))
+		s.attached <- err
+	}()
+
+	err := sources.TarGz(ctx, w, s.artifact, s.dependencies)
+	w.CloseWithError(err)
+
+	return errors.Wrap(err, "streaming buildcontext")
+}
+
+// Cleanup waits for kubectl attach to exit
+func (s *Stdin) Cleanup(ctx context.Context) error {
+	if s.attached == nil {
+		return nil
+	}
+
+	select {
+	case err := <-s.attached:
+		if err != nil {
+			logrus.Debugln("kubectl attach:", err)
+		}
+	case <-ctx.Done():
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sources"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStdin(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", "FROM busybox").
+		Write("app.go", "package main")
+
+	artifact := &latest.Artifact{Workspace: tmpDir.Root()}
+	dependencies := []string{tmpDir.Path("Dockerfile"), tmpDir.Path("app.go")}
+
+	var expected bytes.Buffer
+	err := sources.TarGz(context.Background(), &expected, artifact, dependencies)
+	testutil.CheckError(t, false, err)
+
+	s := Retrieve(&latest.KanikoBuild{
+		BuildContext: &latest.KanikoBuildContext{Stdin: &latest.StdinContext{}},
+	}, "kubecontext").(*Stdin)
+
+	buildContext, err := s.Setup(context.Background(), ioutil.Discard, artifact, "tag", dependencies)
+	testutil.CheckErrorAndDeepEqual(t, false, err, "tar://stdin", buildContext)
+
+	pod := s.Pod([]string{"--context", buildContext})
+	testutil.CheckDeepEqual(t, true, pod.Spec.Containers[0].Stdin)
+	testutil.CheckDeepEqual(t, true, pod.Spec.Containers[0].StdinOnce)
+	testutil.CheckDeepEqual(t, 0, len(pod.Spec.InitContainers))
+
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).WithRunInput("kubectl --context kubecontext attach -i kaniko-1234 -c kaniko -n ns", expected.String())
+
+	err = s.stream(context.Background(), &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "kaniko-1234", Namespace: "ns"}})
+	testutil.CheckError(t, false, err)
+
+	err = s.Cleanup(context.Background())
+	testutil.CheckError(t, false, err)
+}
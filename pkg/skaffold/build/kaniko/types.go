@@ -31,20 +31,30 @@ import (
 type Builder struct {
 	*latest.KanikoBuild
 
-	timeout time.Duration
+	kubeContext string
+	timeout     time.Duration
+	warmPod     *warmPod
 }
 
 // NewBuilder creates a new Builder that builds artifacts with Kaniko.
-func NewBuilder(cfg *latest.KanikoBuild) (*Builder, error) {
+// When reusePod is true, the builds run in a single pod that's kept
+// running until the Builder is stopped.
+func NewBuilder(cfg *latest.KanikoBuild, kubeContext string, reusePod bool) (*Builder, error) {
 	timeout, err := time.ParseDuration(cfg.Timeout)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing timeout")
 	}
 
-	return &Builder{
+	b := &Builder{
 		KanikoBuild: cfg,
+		kubeContext: kubeContext,
 		timeout:     timeout,
-	}, nil
+	}
+	if reusePod {
+		b.warmPod = &warmPod{kubeContext: kubeContext}
+	}
+
+	return b, nil
 }
 
 // Labels are labels specific to Kaniko builder.
@@ -0,0 +1,246 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kaniko
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/kaniko/sources"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// warmPod is a Kaniko pod that is kept running between builds.
+// Each build copies its context into the pod and runs the Kaniko
+// executor with kubectl exec.
+type warmPod struct {
+	// Kaniko modifies the container's filesystem, so
+	// builds have to run one at a time.
+	lock sync.Mutex
+
+	kubeContext string
+	pod         *v1.Pod
+	teardown    func() error
+	// base images already stored in the pod's cache directory
+	cached map[string]bool
+}
+
+// start creates the pod, unless it's already running.
+func (w *warmPod) start(ctx context.Context, out io.Writer, b *Builder) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.pod != nil {
+		return nil
+	}
+
+	teardownSecrets, err := b.setupSecrets(out)
+	if err != nil {
+		return err
+	}
+
+	client, err := kubernetes.GetClientset()
+	if err != nil {
+		teardownSecrets()
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+	pods := client.CoreV1().Pods(b.Namespace)
+
+	pod, err := pods.Create(sources.WarmPod(b.KanikoBuild))
+	if err != nil {
+		teardownSecrets()
+		return errors.Wrap(err, "creating kaniko pod")
+	}
+	teardown := func() error {
+		defer teardownSecrets()
+
+		return pods.Delete(pod.Name, &metav1.DeleteOptions{
+			GracePeriodSeconds: new(int64),
+		})
+	}
+
+	color.Default.Fprintf(out, "Starting kaniko pod [%s]...\n", pod.Name)
+	if err := kubernetes.WaitForPodReady(ctx, pods, pod.Name); err != nil {
+		if err := teardown(); err != nil {
+			logrus.Warnln("deleting kaniko pod:", err)
+		}
+		return errors.Wrap(err, "waiting for kaniko pod to be ready")
+	}
+
+	w.pod = pod
+	w.teardown = teardown
+	w.cached = map[string]bool{}
+	return nil
+}
+
+// stop deletes the pod.
+func (w *warmPod) stop() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.delete()
+}
+
+// delete deletes the pod, if it's running. The caller must hold the lock.
+func (w *warmPod) delete() error {
+	if w.pod == nil {
+		return nil
+	}
+
+	err := w.teardown()
+	w.pod = nil
+	w.teardown = nil
+	w.cached = nil
+
+	return errors.Wrap(err, "deleting kaniko pod")
+}
+
+// run builds an artifact inside the pod and returns the digest of the pushed image.
+func (w *warmPod) run(ctx context.Context, out io.Writer, b *Builder, artifact *latest.Artifact, tag, platform string) (string, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.pod == nil {
+		return "", errors.New("kaniko pod is not running")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	s, ok := sources.Retrieve(b.KanikoBuild, b.kubeContext).(*sources.LocalDir)
+	if !ok {
+		return "", errors.New("reusing the kaniko pod requires a localDir build context")
+	}
+
+	dependencies, err := b.DependenciesForArtifact(ctx, artifact)
+	if err != nil {
+		return "", errors.Wrapf(err, "getting dependencies for %s", artifact.ImageName)
+	}
+
+	id := util.RandomID()
+	if _, err := s.Setup(ctx, out, artifact, id, dependencies); err != nil {
+		return "", errors.Wrap(err, "setting up build context")
+	}
+	defer s.Cleanup(ctx)
+
+	// Each build gets its own context directory in the empty dir
+	dir := path.Join(constants.DefaultKanikoEmptyDirMountPath, id)
+	if err := s.CopyToPod(ctx, w.pod, constants.DefaultKanikoContainerName, dir); err != nil {
+		return "", errors.Wrap(err, "copying build context")
+	}
+	defer func() {
+		if w.pod == nil {
+			return
+		}
+		if err := w.exec(context.Background(), ioutil.Discard, "rm", "-rf", dir); err != nil {
+			logrus.Warnln("deleting build context:", err)
+		}
+	}()
+
+	w.cacheBaseImages(ctx, out, artifact)
+
+	if err := w.runExecutor(ctx, out, executorCommand(b, artifact, dir, tag, platform)); err != nil {
+		return "", errors.Wrap(err, "running kaniko")
+	}
+
+	return docker.RemoteDigest(tag)
+}
+
+// runExecutor runs the Kaniko executor in the pod. When the build times out
+// or is cancelled, only the local kubectl process is killed while the executor
+// keeps running in the pod. The pod is then deleted, so that the next build
+// doesn't run over a half built filesystem. It's recreated on the next build.
+func (w *warmPod) runExecutor(ctx context.Context, out io.Writer, command []string) error {
+	err := w.exec(ctx, out, command...)
+	if err != nil && ctx.Err() != nil {
+		if err := w.delete(); err != nil {
+			logrus.Warnln(err)
+		}
+	}
+
+	return err
+}
+
+// cacheBaseImages runs the Kaniko warmer in the pod to store the base images
+// of the artifact's Dockerfile in the cache directory, where the executor
+// looks for them before pulling them. Failing to do so isn't fatal: the
+// executor then pulls the base images, for example with images that don't
+// contain the warmer.
+func (w *warmPod) cacheBaseImages(ctx context.Context, out io.Writer, artifact *latest.Artifact) {
+	images, err := docker.BaseImages(artifact.Workspace, artifact.DockerArtifact)
+	if err != nil {
+		logrus.Warnln("listing base images:", err)
+		return
+	}
+
+	args := []string{"/kaniko/warmer", "--cache-dir=" + constants.DefaultKanikoCacheDirMountPath}
+	var missing []string
+	for _, image := range images {
+		if !w.cached[image] {
+			args = append(args, "--image="+image)
+			missing = append(missing, image)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	if err := w.exec(ctx, out, args...); err != nil {
+		logrus.Warnln("caching base images:", err)
+		return
+	}
+
+	for _, image := range missing {
+		w.cached[image] = true
+	}
+}
+
+// executorCommand is the command line of a Kaniko executor that builds
+// an artifact from a context directory of the pod.
+func executorCommand(b *Builder, artifact *latest.Artifact, dir, tag, platform string) []string {
+	args := []string{"/kaniko/executor"}
+	args = append(args, b.args(artifact, "dir://"+dir, tag, platform)...)
+	// Restore the container's filesystem for the next build
+	args = append(args, "--cleanup")
+	// Use the base images cached by the warmer
+	args = append(args, "--cache-dir="+constants.DefaultKanikoCacheDirMountPath)
+
+	return args
+}
+
+func (w *warmPod) exec(ctx context.Context, out io.Writer, args ...string) error {
+	args = append([]string{"--context", w.kubeContext, "exec", w.pod.Name, "-c", constants.DefaultKanikoContainerName, "-n", w.pod.Namespace, "--"}, args...)
+
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	return util.RunCmd(cmd)
+}
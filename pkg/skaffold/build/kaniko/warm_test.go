@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kaniko
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExecutorCommand(t *testing.T) {
+	b := &Builder{
+		KanikoBuild: &latest.KanikoBuild{
+			AdditionalFlags: []string{"--reproducible"},
+		},
+	}
+	artifact := &latest.Artifact{
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+		},
+	}
+
+	command := executorCommand(b, artifact, "/kaniko/buildcontext/abcd", "gcr.io/project/image:tag", "")
+
+	testutil.CheckDeepEqual(t, []string{
+		"/kaniko/executor",
+		"--dockerfile", "Dockerfile",
+		"--context", "dir:///kaniko/buildcontext/abcd",
+		"--destination", "gcr.io/project/image:tag",
+		"-v", "info",
+		"--reproducible",
+		"--cleanup",
+		"--cache-dir=/cache",
+	}, command)
+}
+
+func TestCacheBaseImages(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", "FROM golang AS builder\nFROM builder\nFROM gcr.io/distroless/base")
+
+	artifact := &latest.Artifact{
+		Workspace: tmpDir.Root(),
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+		},
+	}
+	w := &warmPod{
+		kubeContext: "kubecontext",
+		pod:         &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "kaniko-warm", Namespace: "ns"}},
+		cached:      map[string]bool{"golang": true},
+	}
+
+	// Only the images that are not yet cached are warmed, and only once.
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).
+		WithRun("kubectl --context kubecontext exec kaniko-warm -c kaniko -n ns -- /kaniko/warmer --cache-dir=/cache --image=gcr.io/distroless/base")
+
+	w.cacheBaseImages(context.Background(), ioutil.Discard, artifact)
+	w.cacheBaseImages(context.Background(), ioutil.Discard, artifact)
+
+	testutil.CheckDeepEqual(t, map[string]bool{"golang": true, "gcr.io/distroless/base": true}, w.cached)
+}
+
+func TestCacheBaseImagesFailure(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", "FROM busybox")
+
+	artifact := &latest.Artifact{
+		Workspace: tmpDir.Root(),
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+		},
+	}
+	w := &warmPod{
+		kubeContext: "kubecontext",
+		pod:         &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "kaniko-warm", Namespace: "ns"}},
+		cached:      map[string]bool{},
+	}
+
+	// Without a warmer, the images are not marked as cached.
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).
+		WithRunErr("kubectl --context kubecontext exec kaniko-warm -c kaniko -n ns -- /kaniko/warmer --cache-dir=/cache --image=busybox", errors.New("no such file"))
+
+	w.cacheBaseImages(context.Background(), ioutil.Discard, artifact)
+
+	testutil.CheckDeepEqual(t, map[string]bool{}, w.cached)
+}
+
+func TestRunExecutorTimeout(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+
+	deleted := false
+	w := &warmPod{
+		kubeContext: "kubecontext",
+		pod:         &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "kaniko-warm", Namespace: "ns"}},
+		teardown: func() error {
+			deleted = true
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).
+		WithRunErr("kubectl --context kubecontext exec kaniko-warm -c kaniko -n ns -- /kaniko/executor", errors.New("killed"))
+
+	err := w.runExecutor(ctx, ioutil.Discard, []string{"/kaniko/executor"})
+
+	testutil.CheckError(t, true, err)
+	testutil.CheckDeepEqual(t, true, deleted)
+	testutil.CheckDeepEqual(t, true, w.pod == nil)
+}
+
+func TestRunExecutorFailure(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+
+	w := &warmPod{
+		kubeContext: "kubecontext",
+		pod:         &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "kaniko-warm", Namespace: "ns"}},
+	}
+
+	// A failed build keeps the pod: the executor has exited.
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).
+		WithRunErr("kubectl --context kubecontext exec kaniko-warm -c kaniko -n ns -- /kaniko/executor", errors.New("build failed"))
+
+	err := w.runExecutor(context.Background(), ioutil.Discard, []string{"/kaniko/executor"})
+
+	testutil.CheckError(t, true, err)
+	testutil.CheckDeepEqual(t, false, w.pod == nil)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/custom"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+func (b *Builder) buildCustom(ctx context.Context, out io.Writer, workspace string, a *latest.CustomArtifact, tag string) (string, error) {
+	env, err := custom.BuildEnv(workspace, tag, b.pushImages)
+	if err != nil {
+		return "", errors.Wrap(err, "creating build environment")
+	}
+
+	cmd := custom.Command(ctx, workspace, a.BuildCommand)
+	cmd.Env = append(append(os.Environ(), b.localDocker.ExtraEnv()...), env...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	logrus.Infof("Building %s: %s", workspace, a.BuildCommand)
+	if err := util.RunCmd(cmd); err != nil {
+		return "", errors.Wrap(err, "running custom build command")
+	}
+
+	if b.pushImages {
+		return docker.RemoteDigest(tag)
+	}
+
+	imageID, err := b.localDocker.ImageID(ctx, tag)
+	if err != nil {
+		return "", err
+	}
+	if imageID == "" {
+		return "", errors.Errorf("the custom build command didn't produce the image %s", tag)
+	}
+
+	return imageID, nil
+}
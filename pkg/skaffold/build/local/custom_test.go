@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestBuildCustom(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+
+	var tests = []struct {
+		description string
+		command     util.Command
+		images      map[string]string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "build",
+			command:     testutil.NewFakeCmd(t).WithRun("sh -c ./build.sh"),
+			images:      map[string]string{"image:tag": "sha256:imageID"},
+			expected:    "sha256:imageID",
+		},
+		{
+			description: "command failure",
+			command:     testutil.NewFakeCmd(t).WithRunErr("sh -c ./build.sh", errors.New("BUG")),
+			shouldErr:   true,
+		},
+		{
+			description: "no image produced",
+			command:     testutil.NewFakeCmd(t).WithRun("sh -c ./build.sh"),
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			util.DefaultExecCommand = test.command
+
+			b := Builder{
+				cfg:         &latest.LocalBuild{},
+				localDocker: docker.NewLocalDaemon(&testutil.FakeAPIClient{TagToImageID: test.images}, nil),
+			}
+
+			imageID, err := b.buildCustom(context.Background(), ioutil.Discard, ".", &latest.CustomArtifact{
+				BuildCommand: "./build.sh",
+			}, "image:tag")
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, imageID)
+		})
+	}
+}
@@ -19,6 +19,7 @@ package local
 import (
 	"context"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 
@@ -30,6 +31,10 @@ import (
 )
 
 func (b *Builder) buildDocker(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, tag string) (string, error) {
+	if docker.UsesBuildKitFeatures(a) && !b.cfg.UseBuildkit {
+		return "", errors.New("secrets and ssh can only be used with `useBuildkit: true`")
+	}
+
 	if err := b.pullCacheFromImages(ctx, out, a); err != nil {
 		return "", errors.Wrap(err, "pulling cache-from images")
 	}
@@ -39,9 +44,12 @@ func (b *Builder) buildDocker(ctx context.Context, out io.Writer, workspace stri
 		err     error
 	)
 
-	if b.cfg.UseDockerCLI || b.cfg.UseBuildkit {
-		imageID, err = b.dockerCLIBuild(ctx, out, workspace, a, tag)
-	} else {
+	switch {
+	case b.cfg.Engine != "" && b.cfg.Engine != docker.DockerEngine:
+		imageID, err = b.engineBuild(ctx, out, workspace, a, tag)
+	case b.cfg.UseDockerCLI || b.cfg.UseBuildkit || b.cfg.PinBaseImages:
+		imageID, err = b.dockerCLIBuild(ctx, out, workspace, a, tag, "")
+	default:
 		imageID, err = b.localDocker.Build(ctx, out, workspace, a, tag)
 	}
 
@@ -52,14 +60,73 @@ func (b *Builder) buildDocker(ctx context.Context, out io.Writer, workspace stri
 	return imageID, err
 }
 
-func (b *Builder) dockerCLIBuild(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, tag string) (string, error) {
+// buildDockerPlatforms builds and pushes a Docker artifact for each of its platforms
+// and then pushes a manifest list that references all those images.
+// It returns the digest of the manifest list.
+func (b *Builder) buildDockerPlatforms(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
+	if !b.pushImages {
+		return "", errors.New("multi-platform images can only be built when pushing to a registry")
+	}
+	if !b.cfg.UseBuildkit {
+		return "", errors.New("multi-platform images can only be built with `useBuildkit: true`")
+	}
+
+	if err := b.pullCacheFromImages(ctx, out, artifact.DockerArtifact); err != nil {
+		return "", errors.Wrap(err, "pulling cache-from images")
+	}
+
+	var images []docker.PlatformImage
+	for _, platform := range artifact.Platforms {
+		platformTag, err := docker.PlatformTag(tag, platform)
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := b.dockerCLIBuild(ctx, out, artifact.Workspace, artifact.DockerArtifact, platformTag, platform); err != nil {
+			return "", errors.Wrapf(err, "building for %s", platform)
+		}
+
+		if _, err := b.localDocker.Push(ctx, out, platformTag); err != nil {
+			return "", errors.Wrapf(err, "pushing image for %s", platform)
+		}
+
+		images = append(images, docker.PlatformImage{
+			Platform: platform,
+			Tag:      platformTag,
+		})
+	}
+
+	return docker.CreateManifestList(tag, images)
+}
+
+func (b *Builder) dockerCLIBuild(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, tag, platform string) (string, error) {
 	dockerfilePath, err := docker.NormalizeDockerfilePath(workspace, a.DockerfilePath)
 	if err != nil {
 		return "", errors.Wrap(err, "normalizing dockerfile path")
 	}
 
+	if b.cfg.PinBaseImages {
+		pinned, cleanup, err := pinnedDockerfile(workspace, a)
+		if err != nil {
+			return "", errors.Wrap(err, "pinning base images")
+		}
+		defer cleanup()
+		dockerfilePath = pinned
+	}
+
 	args := []string{"build", workspace, "--file", dockerfilePath, "-t", tag}
 	args = append(args, docker.GetBuildArgs(a)...)
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+
+	if b.cfg.UseBuildkit {
+		buildKitArgs, err := docker.GetBuildKitArgs(workspace, a)
+		if err != nil {
+			return "", errors.Wrap(err, "getting BuildKit args")
+		}
+		args = append(args, buildKitArgs...)
+	}
 
 	cmd := exec.CommandContext(ctx, "docker", args...)
 	if b.cfg.UseBuildkit {
@@ -75,6 +142,50 @@ func (b *Builder) dockerCLIBuild(ctx context.Context, out io.Writer, workspace s
 	return b.localDocker.ImageID(ctx, tag)
 }
 
+// engineBuild builds with a container engine other than Docker.
+func (b *Builder) engineBuild(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, tag string) (string, error) {
+	if b.cfg.PinBaseImages {
+		pinned, cleanup, err := pinnedDockerfile(workspace, a)
+		if err != nil {
+			return "", errors.Wrap(err, "pinning base images")
+		}
+		defer cleanup()
+
+		withPinnedDockerfile := *a
+		withPinnedDockerfile.DockerfilePath = pinned
+		a = &withPinnedDockerfile
+	}
+
+	return b.localDocker.Build(ctx, out, workspace, a, tag)
+}
+
+// pinnedDockerfile writes, outside of the workspace, a copy of the Dockerfile
+// where the base images are pinned to their current digest.
+func pinnedDockerfile(workspace string, a *latest.DockerArtifact) (string, func(), error) {
+	content, err := docker.PinBaseImages(workspace, a)
+	if err != nil {
+		return "", nil, err
+	}
+
+	f, err := ioutil.TempFile("", "Dockerfile")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "creating temporary Dockerfile")
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		cleanup()
+		return "", nil, errors.Wrap(err, "writing temporary Dockerfile")
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, errors.Wrap(err, "writing temporary Dockerfile")
+	}
+
+	return f.Name(), cleanup, nil
+}
+
 func (b *Builder) pullCacheFromImages(ctx context.Context, out io.Writer, a *latest.DockerArtifact) error {
 	if len(a.CacheFrom) == 0 {
 		return nil
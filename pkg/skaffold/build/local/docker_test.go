@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+func TestDockerCLIBuildWithBuildKit(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", "FROM busybox")
+
+	artifact := &latest.DockerArtifact{
+		DockerfilePath: "Dockerfile",
+		Secrets:        []*latest.DockerSecret{{ID: "token", Env: "TOKEN"}},
+		SSH:            []string{"default"},
+	}
+
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).WithRun("docker build " + tmpDir.Root() + " --file " + tmpDir.Path("Dockerfile") + " -t image:tag --secret id=token,env=TOKEN --ssh default")
+
+	b := Builder{
+		cfg:         &latest.LocalBuild{UseBuildkit: true},
+		localDocker: docker.NewLocalDaemon(&testutil.FakeAPIClient{TagToImageID: map[string]string{"image:tag": "sha256:imageID"}}, nil),
+	}
+	imageID, err := b.buildDocker(context.Background(), ioutil.Discard, tmpDir.Root(), artifact, "image:tag")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "sha256:imageID", imageID)
+
+	// Without BuildKit
+	b.cfg.UseBuildkit = false
+	_, err = b.buildDocker(context.Background(), ioutil.Discard, tmpDir.Root(), artifact, "image:tag")
+	testutil.CheckError(t, true, err)
+}
+
+type fakePlatformImage struct {
+	v1.Image
+}
+
+func (i *fakePlatformImage) RawManifest() ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+func (i *fakePlatformImage) MediaType() (types.MediaType, error) {
+	return types.DockerManifestSchema2, nil
+}
+
+func (i *fakePlatformImage) Digest() (v1.Hash, error) {
+	return v1.NewHash("sha256:0000000000000000000000000000000000000000000000000000000000000000")
+}
+
+func TestDockerBuildPlatforms(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	defer func(r func(string) (v1.Image, error)) { docker.RetrievePlatformImage = r }(docker.RetrievePlatformImage)
+	defer func(w func(string, types.MediaType, []byte) error) { docker.WriteManifestList = w }(docker.WriteManifestList)
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", "FROM busybox")
+
+	artifact := &latest.Artifact{
+		ImageName: "gcr.io/image",
+		Workspace: tmpDir.Root(),
+		Platforms: []string{"linux/amd64", "linux/arm64"},
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+		},
+	}
+
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).
+		WithRun("docker build " + tmpDir.Root() + " --file " + tmpDir.Path("Dockerfile") + " -t gcr.io/image:tag-linux-amd64 --platform linux/amd64").
+		WithRun("docker build " + tmpDir.Root() + " --file " + tmpDir.Path("Dockerfile") + " -t gcr.io/image:tag-linux-arm64 --platform linux/arm64")
+
+	var retrieved []string
+	docker.RetrievePlatformImage = func(tag string) (v1.Image, error) {
+		retrieved = append(retrieved, tag)
+		return &fakePlatformImage{}, nil
+	}
+	var pushedList string
+	docker.WriteManifestList = func(tag string, _ types.MediaType, _ []byte) error {
+		pushedList = tag
+		return nil
+	}
+
+	b := Builder{
+		cfg:         &latest.LocalBuild{UseBuildkit: true},
+		localDocker: docker.NewLocalDaemon(&testutil.FakeAPIClient{}, nil),
+		pushImages:  true,
+	}
+	digest, err := b.runBuildForArtifact(context.Background(), ioutil.Discard, artifact, "gcr.io/image:tag")
+
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, true, strings.HasPrefix(digest, "sha256:"))
+	testutil.CheckDeepEqual(t, []string{"gcr.io/image:tag-linux-amd64", "gcr.io/image:tag-linux-arm64"}, retrieved)
+	testutil.CheckDeepEqual(t, "gcr.io/image:tag", pushedList)
+
+	// Without pushing
+	b.pushImages = false
+	_, err = b.runBuildForArtifact(context.Background(), ioutil.Discard, artifact, "gcr.io/image:tag")
+	testutil.CheckError(t, true, err)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/goimage"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+)
+
+func (b *Builder) buildGoImage(ctx context.Context, out io.Writer, workspace string, a *latest.GoImageArtifact, tag string) (string, error) {
+	img, err := goimage.Build(ctx, out, workspace, a)
+	if err != nil {
+		return "", err
+	}
+
+	t, err := name.NewTag(tag, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing tag %q", tag)
+	}
+
+	if b.pushImages {
+		return pushGoImage(t, img)
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(tarball.Write(t, img, w))
+	}()
+
+	imageID, err := b.localDocker.Load(ctx, out, r, tag)
+	r.Close()
+	if err != nil {
+		return "", errors.Wrap(err, "loading image into docker daemon")
+	}
+
+	return imageID, nil
+}
+
+func pushGoImage(t name.Tag, img v1.Image) (string, error) {
+	auth, err := authn.DefaultKeychain.Resolve(t.Registry)
+	if err != nil {
+		return "", errors.Wrapf(err, "getting creds for %q", t)
+	}
+
+	if err := remote.Write(t, img, auth, http.DefaultTransport); err != nil {
+		return "", errors.Wrapf(err, "writing image %q", t)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", errors.Wrap(err, "getting image digest")
+	}
+
+	return digest.String(), nil
+}
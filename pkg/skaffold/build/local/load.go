@@ -0,0 +1,57 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+
+	configutil "github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/cmd/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+)
+
+// loadImagesInCluster side-loads images into the nodes of a kind or k3d cluster,
+// so that they don't have to be pushed to a registry.
+func (b *Builder) loadImagesInCluster(ctx context.Context, out io.Writer, artifacts []build.Artifact) error {
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	var tags []string
+	for _, a := range artifacts {
+		tags = append(tags, a.Tag)
+	}
+
+	var cmd *exec.Cmd
+	if cluster, isKind := configutil.KindCluster(b.kubeContext); isKind {
+		color.Default.Fprintf(out, "Loading images into kind cluster [%s]...\n", cluster)
+		cmd = exec.CommandContext(ctx, "kind", append([]string{"load", "docker-image", "--name", cluster}, tags...)...)
+	} else if cluster, isK3d := configutil.K3dCluster(b.kubeContext); isK3d {
+		color.Default.Fprintf(out, "Loading images into k3d cluster [%s]...\n", cluster)
+		cmd = exec.CommandContext(ctx, "k3d", append([]string{"image", "import", "--cluster", cluster}, tags...)...)
+	} else {
+		return fmt.Errorf("unable to load images into cluster for kube-context %s", b.kubeContext)
+	}
+
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return util.RunCmd(cmd)
+}
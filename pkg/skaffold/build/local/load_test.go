@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestLoadImagesInCluster(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+
+	var tests = []struct {
+		description string
+		kubeContext string
+		command     util.Command
+		shouldErr   bool
+	}{
+		{
+			description: "kind",
+			kubeContext: "kind-dev",
+			command:     testutil.NewFakeCmd(t).WithRun("kind load docker-image --name dev image1:tag image2:tag"),
+		},
+		{
+			description: "legacy kind context",
+			kubeContext: "kubernetes-admin@kind",
+			command:     testutil.NewFakeCmd(t).WithRun("kind load docker-image --name kind image1:tag image2:tag"),
+		},
+		{
+			description: "k3d",
+			kubeContext: "k3d-dev",
+			command:     testutil.NewFakeCmd(t).WithRun("k3d image import --cluster dev image1:tag image2:tag"),
+		},
+		{
+			description: "loading fails",
+			kubeContext: "kind-dev",
+			command:     testutil.NewFakeCmd(t).WithRunErr("kind load docker-image --name dev image1:tag image2:tag", errors.New("BUG")),
+			shouldErr:   true,
+		},
+		{
+			description: "unsupported cluster",
+			kubeContext: "minikube",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			util.DefaultExecCommand = test.command
+
+			b := Builder{kubeContext: test.kubeContext}
+
+			err := b.loadImagesInCluster(context.Background(), ioutil.Discard, []build.Artifact{
+				{ImageName: "image1", Tag: "image1:tag"},
+				{ImageName: "image2", Tag: "image2:tag"},
+			})
+
+			testutil.CheckError(t, test.shouldErr, err)
+		})
+	}
+}
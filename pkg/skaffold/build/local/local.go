@@ -25,8 +25,11 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/bazel"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/buildpacks"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/custom"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/goimage"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/jib"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
@@ -42,8 +45,24 @@ func (b *Builder) Build(ctx context.Context, out io.Writer, tags tag.ImageTags,
 	}
 	defer b.localDocker.Close()
 
-	// TODO(dgageot): parallel builds
-	return build.InSequence(ctx, out, tags, artifacts, b.buildArtifact)
+	concurrency := 1
+	if b.cfg.Concurrency != nil {
+		concurrency = *b.cfg.Concurrency
+	}
+	failFast := b.cfg.FailFast == nil || *b.cfg.FailFast
+
+	bRes, err := build.InParallel(ctx, out, tags, artifacts, b.buildArtifact, concurrency, failFast)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.loadImages && !b.pushImages {
+		if err := b.loadImagesInCluster(ctx, out, bRes); err != nil {
+			return nil, errors.Wrap(err, "loading images into the cluster")
+		}
+	}
+
+	return bRes, nil
 }
 
 func (b *Builder) buildArtifact(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
@@ -72,6 +91,9 @@ func (b *Builder) buildArtifact(ctx context.Context, out io.Writer, artifact *la
 
 func (b *Builder) runBuildForArtifact(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
 	switch {
+	case artifact.DockerArtifact != nil && len(artifact.Platforms) > 0:
+		return b.buildDockerPlatforms(ctx, out, artifact, tag)
+
 	case artifact.DockerArtifact != nil:
 		return b.buildDocker(ctx, out, artifact.Workspace, artifact.DockerArtifact, tag)
 
@@ -84,6 +106,15 @@ func (b *Builder) runBuildForArtifact(ctx context.Context, out io.Writer, artifa
 	case artifact.JibGradleArtifact != nil:
 		return b.buildJibGradle(ctx, out, artifact.Workspace, artifact.JibGradleArtifact, tag)
 
+	case artifact.CustomArtifact != nil:
+		return b.buildCustom(ctx, out, artifact.Workspace, artifact.CustomArtifact, tag)
+
+	case artifact.BuildpackArtifact != nil:
+		return b.buildBuildpacks(ctx, out, artifact.Workspace, artifact.BuildpackArtifact, tag)
+
+	case artifact.GoImageArtifact != nil:
+		return b.buildGoImage(ctx, out, artifact.Workspace, artifact.GoImageArtifact, tag)
+
 	default:
 		return "", fmt.Errorf("undefined artifact type: %+v", artifact.ArtifactType)
 	}
@@ -108,6 +139,15 @@ func (b *Builder) DependenciesForArtifact(ctx context.Context, a *latest.Artifac
 	case a.JibGradleArtifact != nil:
 		paths, err = jib.GetDependenciesGradle(ctx, a.Workspace, a.JibGradleArtifact)
 
+	case a.CustomArtifact != nil:
+		paths, err = custom.GetDependencies(ctx, a.Workspace, a.CustomArtifact)
+
+	case a.BuildpackArtifact != nil:
+		paths, err = buildpacks.GetDependencies(ctx, a.Workspace, a.BuildpackArtifact)
+
+	case a.GoImageArtifact != nil:
+		paths, err = goimage.GetDependencies(ctx, a.Workspace, a.GoImageArtifact)
+
 	default:
 		return nil, fmt.Errorf("undefined artifact type: %+v", a.ArtifactType)
 	}
@@ -25,6 +25,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 	"github.com/docker/docker/api/types"
@@ -199,3 +200,100 @@ func TestLocalRun(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldPush(t *testing.T) {
+	var tests = []struct {
+		description  string
+		engine       string
+		push         *bool
+		kubeContext  string
+		localCluster bool
+		loadImages   bool
+		shouldErr    bool
+		expected     bool
+	}{
+		{
+			description: "remote cluster",
+			kubeContext: "gke_project_zone_cluster",
+			expected:    true,
+		},
+		{
+			description:  "minikube",
+			kubeContext:  "minikube",
+			localCluster: true,
+			expected:     false,
+		},
+		{
+			description:  "kind with images loaded",
+			kubeContext:  "kind-kind",
+			localCluster: true,
+			loadImages:   true,
+			expected:     false,
+		},
+		{
+			description:  "kind without images loaded",
+			kubeContext:  "kind-kind",
+			localCluster: true,
+			expected:     true,
+		},
+		{
+			description:  "k3d without images loaded",
+			kubeContext:  "k3d-k3s-default",
+			localCluster: true,
+			expected:     true,
+		},
+		{
+			description:  "push=false and load=false on kind",
+			push:         util.BoolPtr(false),
+			kubeContext:  "kind-kind",
+			localCluster: true,
+			shouldErr:    true,
+		},
+		{
+			description:  "push=false on minikube",
+			push:         util.BoolPtr(false),
+			kubeContext:  "minikube",
+			localCluster: true,
+			expected:     false,
+		},
+		{
+			description:  "push=true on kind",
+			push:         util.BoolPtr(true),
+			kubeContext:  "kind-kind",
+			localCluster: true,
+			loadImages:   true,
+			expected:     true,
+		},
+		{
+			description:  "podman on minikube",
+			engine:       "podman",
+			kubeContext:  "minikube",
+			localCluster: true,
+			expected:     true,
+		},
+		{
+			description:  "buildah on kind with images loaded",
+			engine:       "buildah",
+			kubeContext:  "kind-kind",
+			localCluster: true,
+			loadImages:   true,
+			expected:     true,
+		},
+		{
+			description:  "push=false with podman",
+			engine:       "podman",
+			push:         util.BoolPtr(false),
+			kubeContext:  "docker-desktop",
+			localCluster: true,
+			shouldErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			push, err := shouldPush(&latest.LocalBuild{Engine: test.engine, Push: test.push}, test.kubeContext, test.localCluster, test.loadImages)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, push)
+		})
+	}
+}
@@ -35,15 +35,16 @@ type Builder struct {
 	localDocker  docker.LocalDaemon
 	localCluster bool
 	pushImages   bool
+	loadImages   bool
 	skipTests    bool
 	kubeContext  string
 }
 
 // NewBuilder returns an new instance of a local Builder.
 func NewBuilder(cfg *latest.LocalBuild, kubeContext string, skipTests bool) (*Builder, error) {
-	localDocker, err := docker.NewAPIClient()
+	localDocker, err := docker.NewLocalEngine(cfg.Engine)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting docker client")
+		return nil, errors.Wrap(err, "getting container engine")
 	}
 
 	localCluster, err := configutil.GetLocalCluster()
@@ -51,12 +52,18 @@ func NewBuilder(cfg *latest.LocalBuild, kubeContext string, skipTests bool) (*Bu
 		return nil, errors.Wrap(err, "getting localCluster")
 	}
 
-	var pushImages bool
-	if cfg.Push == nil {
-		pushImages = !localCluster
-		logrus.Debugf("push value not present, defaulting to %t because localCluster is %t", pushImages, localCluster)
-	} else {
-		pushImages = *cfg.Push
+	loadImages, err := configutil.GetLoadImages()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting loadImages")
+	}
+	// Images can only be loaded into kind or k3d from the Docker daemon
+	if !usesDocker(cfg) {
+		loadImages = false
+	}
+
+	pushImages, err := shouldPush(cfg, kubeContext, localCluster, loadImages)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Builder{
@@ -65,10 +72,43 @@ func NewBuilder(cfg *latest.LocalBuild, kubeContext string, skipTests bool) (*Bu
 		localDocker:  localDocker,
 		localCluster: localCluster,
 		pushImages:   pushImages,
+		loadImages:   loadImages,
 		skipTests:    skipTests,
 	}, nil
 }
 
+// shouldPush tells if the images should be pushed. Images built for
+// kind or k3d clusters have to be either pushed or loaded into the cluster.
+// Images built with Podman or Buildah are always pushed since no cluster
+// can read them from the engine's storage.
+func shouldPush(cfg *latest.LocalBuild, kubeContext string, localCluster, loadImages bool) (bool, error) {
+	if !usesDocker(cfg) {
+		if cfg.Push != nil && !*cfg.Push {
+			return false, fmt.Errorf("images built with the %s engine have to be pushed", cfg.Engine)
+		}
+		return true, nil
+	}
+
+	_, isKind := configutil.KindCluster(kubeContext)
+	_, isK3d := configutil.K3dCluster(kubeContext)
+	needsLoading := isKind || isK3d
+
+	if cfg.Push != nil {
+		if !*cfg.Push && needsLoading && !loadImages {
+			return false, fmt.Errorf("images built for the %s context have to be pushed or loaded: set `push: true` or `load-images: true`", kubeContext)
+		}
+		return *cfg.Push, nil
+	}
+
+	pushImages := !localCluster || (needsLoading && !loadImages)
+	logrus.Debugf("push value not present, defaulting to %t because localCluster is %t and loadImages is %t", pushImages, localCluster, loadImages)
+	return pushImages, nil
+}
+
+func usesDocker(cfg *latest.LocalBuild) bool {
+	return cfg.Engine == "" || cfg.Engine == docker.DockerEngine
+}
+
 // Labels are labels specific to local builder.
 func (b *Builder) Labels() map[string]string {
 	labels := map[string]string{
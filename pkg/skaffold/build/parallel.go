@@ -21,6 +21,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
@@ -28,34 +30,100 @@ import (
 	"github.com/pkg/errors"
 )
 
-const bufferedLinesPerArtifact = 10000
-
 type artifactBuilder func(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error)
 
 // InParallel builds a list of artifacts in parallel but prints the logs in sequential order.
-func InParallel(ctx context.Context, out io.Writer, tags tag.ImageTags, artifacts []*latest.Artifact, buildArtifact artifactBuilder) ([]Artifact, error) {
-	if len(artifacts) == 1 {
+// Each artifact is built as soon as the artifacts it requires are built.
+// At most `concurrency` artifacts are built at the same time, 0 meaning no limit.
+// With `failFast`, the first failure stops all the builds. Otherwise, all the
+// artifacts are built and all the failures are reported.
+// Results are returned in an order where required artifacts come first,
+// not in the order of the given artifacts.
+func InParallel(ctx context.Context, out io.Writer, tags tag.ImageTags, artifacts []*latest.Artifact, buildArtifact artifactBuilder, concurrency int, failFast bool) ([]Artifact, error) {
+	if len(artifacts) == 1 || (concurrency == 1 && failFast) {
 		return InSequence(ctx, out, tags, artifacts, buildArtifact)
 	}
 
+	// Logs are printed in an order where required artifacts come first.
+	artifacts, err := sortArtifacts(artifacts)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	n := len(artifacts)
 	finalTags := make([]string, n)
 	errs := make([]error, n)
-	outputs := make([]chan []byte, n)
+	outputs := make([]*lineBuffer, n)
+	done := make([]chan struct{}, n)
+
+	indexes := map[string]int{}
+	for i, artifact := range artifacts {
+		indexes[artifact.ImageName] = i
+		done[i] = make(chan struct{})
+	}
+
+	// Limit the number of concurrent builds
+	if concurrency <= 0 || concurrency > n {
+		concurrency = n
+	}
+	slots := make(chan struct{}, concurrency)
+
+	// With failFast, the first failure cancels the other builds.
+	var firstFailure sync.Once
+	failed := -1
+	fail := func(i int, err error) {
+		errs[i] = err
+		if failFast {
+			firstFailure.Do(func() {
+				failed = i
+				cancel()
+			})
+		}
+	}
+
+	// withRequirements waits for the required artifacts to be built
+	// and passes the fully qualified names of their images to the artifact.
+	withRequirements := func(artifact *latest.Artifact) (*latest.Artifact, error) {
+		images := imagesByName(tags)
+
+		for _, r := range artifact.Requires {
+			j, present := indexes[r.ImageName]
+			if !present {
+				continue
+			}
+
+			select {
+			case <-done[j]:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+
+			if errs[j] != nil {
+				return nil, fmt.Errorf("required artifact %s failed to build", r.ImageName)
+			}
+			images[r.ImageName] = finalTags[j]
+		}
+
+		return withRequiredImages(artifact, images)
+	}
 
 	// Run builds in //
 	for index := range artifacts {
 		i := index
-		lines := make(chan []byte, bufferedLinesPerArtifact)
+		// Logs are buffered without limit so that a build never blocks
+		// on its output while it holds a slot.
+		lines := newLineBuffer()
 		outputs[i] = lines
 
 		r, w := io.Pipe()
 
 		// Log to the pipe, output will be collected and printed later
 		go func() {
+			defer close(done[i])
+
 			// Make sure logs are printed in colors
 			var cw io.WriteCloser
 			if color.IsTerminal(out) {
@@ -63,39 +131,63 @@ func InParallel(ctx context.Context, out io.Writer, tags tag.ImageTags, artifact
 			} else {
 				cw = w
 			}
+			defer cw.Close()
 
 			color.Default.Fprintf(cw, "Building [%s]...\n", artifacts[i].ImageName)
 
 			tag, present := tags[artifacts[i].ImageName]
 			if !present {
-				errs[i] = fmt.Errorf("unable to find tag for image %s", artifacts[i].ImageName)
-			} else {
-				finalTags[i], errs[i] = buildArtifact(ctx, cw, artifacts[i], tag)
+				fail(i, fmt.Errorf("unable to find tag for image %s", artifacts[i].ImageName))
+				return
+			}
+
+			artifact, err := withRequirements(artifacts[i])
+			if err != nil {
+				fail(i, err)
+				return
+			}
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				fail(i, ctx.Err())
+				return
 			}
 
-			cw.Close()
+			finalTags[i], err = buildArtifact(ctx, cw, artifact, tag)
+			if err != nil {
+				fail(i, err)
+			}
 		}()
 
 		go func() {
 			scanner := bufio.NewScanner(r)
 			for scanner.Scan() {
-				lines <- scanner.Bytes()
+				lines.add(scanner.Bytes())
 			}
-			close(lines)
+			lines.close()
 		}()
 	}
 
 	// Print logs and collect results in order.
 	var built []Artifact
+	var failures []string
 
 	for i, artifact := range artifacts {
-		for line := range outputs[i] {
+		for line, ok := outputs[i].next(); ok; line, ok = outputs[i].next() {
 			out.Write(line)
 			fmt.Fprintln(out)
 		}
+		<-done[i]
 
 		if errs[i] != nil {
-			return nil, errors.Wrapf(errs[i], "building [%s]", artifact.ImageName)
+			if failFast {
+				return nil, errors.Wrapf(errs[failed], "building [%s]", artifacts[failed].ImageName)
+			}
+
+			failures = append(failures, fmt.Sprintf("building [%s]: %s", artifact.ImageName, errs[i]))
+			continue
 		}
 
 		built = append(built, Artifact{
@@ -104,5 +196,57 @@ func InParallel(ctx context.Context, out io.Writer, tags tag.ImageTags, artifact
 		})
 	}
 
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("%d of %d artifacts failed to build:\n%s", len(failures), n, strings.Join(failures, "\n"))
+	}
+
 	return built, nil
 }
+
+// lineBuffer is an unbounded queue of log lines.
+type lineBuffer struct {
+	lock   sync.Mutex
+	cond   *sync.Cond
+	lines  [][]byte
+	closed bool
+}
+
+func newLineBuffer() *lineBuffer {
+	b := &lineBuffer{}
+	b.cond = sync.NewCond(&b.lock)
+	return b
+}
+
+func (b *lineBuffer) add(line []byte) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.lines = append(b.lines, append([]byte(nil), line...))
+	b.cond.Signal()
+}
+
+func (b *lineBuffer) close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.closed = true
+	b.cond.Signal()
+}
+
+// next waits for the next line. It returns false once the buffer
+// is closed and all the lines have been read.
+func (b *lineBuffer) next() ([]byte, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for len(b.lines) == 0 && !b.closed {
+		b.cond.Wait()
+	}
+	if len(b.lines) == 0 {
+		return nil, false
+	}
+
+	line := b.lines[0]
+	b.lines = b.lines[1:]
+	return line, true
+}
@@ -21,7 +21,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
@@ -78,10 +81,153 @@ func TestInParallel(t *testing.T) {
 				{ImageName: "skaffold/image2"},
 			}
 
-			got, err := InParallel(context.Background(), out, test.tags, artifacts, test.buildArtifact)
+			got, err := InParallel(context.Background(), out, test.tags, artifacts, test.buildArtifact, 0, true)
 
 			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expectedArtifacts, got)
 			testutil.CheckDeepEqual(t, test.expectedOut, out.String())
 		})
 	}
 }
+
+func TestInParallelConcurrency(t *testing.T) {
+	var tests = []struct {
+		description string
+		concurrency int
+		expectedMax int
+	}{
+		{description: "no limit", concurrency: 0, expectedMax: 4},
+		{description: "two at a time", concurrency: 2, expectedMax: 2},
+		{description: "one at a time", concurrency: 1, expectedMax: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var (
+				lock    sync.Mutex
+				running int
+				max     int
+				started = make(chan struct{})
+				once    sync.Once
+			)
+
+			buildArtifact := func(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
+				lock.Lock()
+				running++
+				if running > max {
+					max = running
+				}
+				if running == test.expectedMax {
+					once.Do(func() { close(started) })
+				}
+				lock.Unlock()
+
+				// Wait for as many builds as expected to run at the same time.
+				<-started
+
+				lock.Lock()
+				running--
+				lock.Unlock()
+
+				return tag, nil
+			}
+
+			var artifacts []*latest.Artifact
+			tags := tag.ImageTags{}
+			for i := 1; i <= 4; i++ {
+				imageName := fmt.Sprintf("skaffold/image%d", i)
+				artifacts = append(artifacts, &latest.Artifact{ImageName: imageName})
+				tags[imageName] = imageName + ":tag"
+			}
+
+			built, err := InParallel(context.Background(), ioutil.Discard, tags, artifacts, buildArtifact, test.concurrency, false)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, 4, len(built))
+			testutil.CheckDeepEqual(t, test.expectedMax, max)
+		})
+	}
+}
+
+func TestInParallelFailures(t *testing.T) {
+	artifacts := []*latest.Artifact{
+		{ImageName: "skaffold/image1"},
+		{ImageName: "skaffold/image2"},
+		{ImageName: "skaffold/image3"},
+	}
+	tags := tag.ImageTags{
+		"skaffold/image1": "skaffold/image1:tag",
+		"skaffold/image2": "skaffold/image2:tag",
+		"skaffold/image3": "skaffold/image3:tag",
+	}
+
+	buildArtifact := func(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
+		if artifact.ImageName == "skaffold/image3" {
+			return tag, nil
+		}
+		return "", fmt.Errorf("%s fails", artifact.ImageName)
+	}
+
+	// Build all
+	_, err := InParallel(context.Background(), ioutil.Discard, tags, artifacts, buildArtifact, 0, false)
+	testutil.CheckErrorAndDeepEqual(t, true, err, "2 of 3 artifacts failed to build:\n"+
+		"building [skaffold/image1]: skaffold/image1 fails\n"+
+		"building [skaffold/image2]: skaffold/image2 fails", err.Error())
+
+	// Fail fast
+	_, err = InParallel(context.Background(), ioutil.Discard, tags, artifacts, buildArtifact, 1, true)
+	testutil.CheckErrorAndDeepEqual(t, true, err, "building [skaffold/image1]: skaffold/image1 fails", err.Error())
+}
+
+func TestInParallelVerboseBuilds(t *testing.T) {
+	var tests = []struct {
+		description string
+		concurrency int
+	}{
+		{description: "one at a time", concurrency: 1},
+		{description: "two at a time", concurrency: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var artifacts []*latest.Artifact
+			tags := tag.ImageTags{}
+			for i := 1; i <= 3; i++ {
+				imageName := fmt.Sprintf("skaffold/image%d", i)
+				artifacts = append(artifacts, &latest.Artifact{ImageName: imageName})
+				tags[imageName] = imageName + ":tag"
+			}
+
+			var once sync.Once
+			written := make(chan struct{})
+
+			// Later builds print more lines than a bounded buffer could hold.
+			// With two slots, the first build waits for one of them to be done
+			// with its output, which forces a later build to hold a slot
+			// while the logs of the first build are being printed.
+			buildArtifact := func(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
+				if artifact.ImageName == "skaffold/image1" {
+					if test.concurrency > 1 {
+						<-written
+					}
+					return tag, nil
+				}
+
+				for i := 0; i < 20000; i++ {
+					fmt.Fprintln(out, "line")
+				}
+				once.Do(func() { close(written) })
+				return tag, nil
+			}
+
+			result := make(chan error, 1)
+			go func() {
+				_, err := InParallel(context.Background(), ioutil.Discard, tags, artifacts, buildArtifact, test.concurrency, false)
+				result <- err
+			}()
+
+			select {
+			case err := <-result:
+				testutil.CheckError(t, false, err)
+			case <-time.After(30 * time.Second):
+				t.Fatal("builds are stuck")
+			}
+		})
+	}
+}
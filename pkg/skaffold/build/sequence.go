@@ -28,10 +28,17 @@ import (
 )
 
 // InSequence builds a list of artifacts in sequence.
+// Artifacts are built after the artifacts they require.
 func InSequence(ctx context.Context, out io.Writer, tags tag.ImageTags, artifacts []*latest.Artifact, buildArtifact artifactBuilder) ([]Artifact, error) {
+	sorted, err := sortArtifacts(artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	images := imagesByName(tags)
 	var builds []Artifact
 
-	for _, artifact := range artifacts {
+	for _, artifact := range sorted {
 		color.Default.Fprintf(out, "Building [%s]...\n", artifact.ImageName)
 
 		tag, present := tags[artifact.ImageName]
@@ -39,10 +46,16 @@ func InSequence(ctx context.Context, out io.Writer, tags tag.ImageTags, artifact
 			return nil, fmt.Errorf("unable to find tag for image %s", artifact.ImageName)
 		}
 
-		finalTag, err := buildArtifact(ctx, out, artifact, tag)
+		withImages, err := withRequiredImages(artifact, images)
+		if err != nil {
+			return nil, err
+		}
+
+		finalTag, err := buildArtifact(ctx, out, withImages, tag)
 		if err != nil {
 			return nil, errors.Wrapf(err, "building [%s]", artifact.ImageName)
 		}
+		images[artifact.ImageName] = finalTag
 
 		builds = append(builds, Artifact{
 			ImageName: artifact.ImageName,
@@ -18,8 +18,13 @@ package tag
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
@@ -27,8 +32,39 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Git tagger variants.
+const (
+	Tags            = "Tags"
+	ExactTag        = "ExactTag"
+	CommitSha       = "CommitSha"
+	AbbrevCommitSha = "AbbrevCommitSha"
+	TreeSha         = "TreeSha"
+	AbbrevTreeSha   = "AbbrevTreeSha"
+	BranchName      = "BranchName"
+)
+
+// Characters that are not allowed in a docker tag.
+var invalidTagChars = regexp.MustCompile(`[^\w.-]`)
+
 // GitCommit tags an image by the git commit it was built at.
-type GitCommit struct{}
+type GitCommit struct {
+	variant       string
+	dirtyWithDiff bool
+}
+
+// NewGitCommit creates a new git tagger. It fails if the tagger variant is invalid.
+func NewGitCommit(variant string, dirtyWithDiff bool) (*GitCommit, error) {
+	switch variant {
+	case "", Tags, ExactTag, CommitSha, AbbrevCommitSha, TreeSha, AbbrevTreeSha, BranchName:
+	default:
+		return nil, fmt.Errorf("%s is not a valid git tagger variant", variant)
+	}
+
+	return &GitCommit{
+		variant:       variant,
+		dirtyWithDiff: dirtyWithDiff,
+	}, nil
+}
 
 // Labels are labels specific to the git tagger.
 func (c *GitCommit) Labels() map[string]string {
@@ -39,8 +75,7 @@ func (c *GitCommit) Labels() map[string]string {
 
 // GenerateFullyQualifiedImageName tags an image with the supplied image name and the git commit.
 func (c *GitCommit) GenerateFullyQualifiedImageName(workingDir string, imageName string) (string, error) {
-	hash, err := runGit(workingDir, "rev-parse", "--short", "HEAD")
-	if err != nil {
+	if _, err := runGit(workingDir, "rev-parse", "HEAD"); err != nil {
 		logrus.Warnln("Unable to find git commit:", err)
 		return fmt.Sprintf("%s:dirty", imageName), nil
 	}
@@ -49,18 +84,97 @@ func (c *GitCommit) GenerateFullyQualifiedImageName(workingDir string, imageName
 	if err != nil {
 		return "", errors.Wrap(err, "getting git status")
 	}
+	dirty := len(changes) > 0
+
+	tag, err := c.tag(workingDir, dirty)
+	if err != nil {
+		return "", err
+	}
+
+	if dirty {
+		tag += "-dirty"
 
-	if len(changes) > 0 {
-		return fmt.Sprintf("%s:%s-dirty", imageName, hash), nil
+		if c.dirtyWithDiff {
+			hash, err := diffHash(workingDir)
+			if err != nil {
+				return "", errors.Wrap(err, "hashing uncommitted changes")
+			}
+			tag += "-" + hash
+		}
 	}
 
-	// Ignore error. It means there's no tag.
-	tag, _ := runGit(workingDir, "describe", "--tags", "--exact-match")
-	if len(tag) > 0 {
-		return fmt.Sprintf("%s:%s", imageName, tag), nil
+	return fmt.Sprintf("%s:%s", imageName, tag), nil
+}
+
+// tag computes the tag for the configured variant, without the dirty marker.
+func (c *GitCommit) tag(workingDir string, dirty bool) (string, error) {
+	switch c.variant {
+	case ExactTag:
+		tag, err := runGit(workingDir, "describe", "--tags", "--exact-match")
+		if err != nil {
+			return "", errors.Wrap(err, "the current commit is not tagged")
+		}
+		return tag, nil
+
+	case CommitSha:
+		return runGit(workingDir, "rev-parse", "HEAD")
+
+	case AbbrevCommitSha:
+		return runGit(workingDir, "rev-parse", "--short", "HEAD")
+
+	case TreeSha:
+		return runGit(workingDir, "rev-parse", "HEAD:./")
+
+	case AbbrevTreeSha:
+		return runGit(workingDir, "rev-parse", "--short", "HEAD:./")
+
+	case BranchName:
+		branch, err := runGit(workingDir, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return "", errors.Wrap(err, "getting current branch")
+		}
+		if branch == "HEAD" {
+			return "", errors.New("the workspace is not on a branch")
+		}
+		return invalidTagChars.ReplaceAllString(branch, "-"), nil
+
+	default:
+		// Ignore the tag when the workspace has uncommitted changes.
+		if !dirty {
+			// Ignore error. It means there's no tag.
+			if tag, _ := runGit(workingDir, "describe", "--tags", "--exact-match"); len(tag) > 0 {
+				return tag, nil
+			}
+		}
+		return runGit(workingDir, "rev-parse", "--short", "HEAD")
+	}
+}
+
+// diffHash computes a short hash of the uncommitted changes in the
+// working directory, including the content of untracked files.
+func diffHash(workingDir string) (string, error) {
+	h := sha256.New()
+
+	diff, err := runGit(workingDir, "diff", "--no-color", "--no-ext-diff", "--full-index", "HEAD", "--", ".")
+	if err != nil {
+		return "", err
+	}
+	h.Write([]byte(diff))
+
+	untracked, err := runGit(workingDir, "ls-files", "--others", "--exclude-standard", "--", ".")
+	if err != nil {
+		return "", err
+	}
+	for _, file := range util.NonEmptyLines([]byte(untracked)) {
+		content, err := ioutil.ReadFile(filepath.Join(workingDir, file))
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte(file))
+		h.Write(content)
 	}
 
-	return fmt.Sprintf("%s:%s", imageName, hash), nil
+	return hex.EncodeToString(h.Sum(nil))[:7], nil
 }
 
 func runGit(workingDir string, arg ...string) (string, error) {
@@ -230,6 +230,168 @@ func TestGitCommit_GenerateFullyQualifiedImageName(t *testing.T) {
 	}
 }
 
+func TestGitCommit_Variants(t *testing.T) {
+	tests := []struct {
+		description   string
+		variant       string
+		dirtyWithDiff bool
+		expectedName  string
+		createGitRepo func(string)
+		subDir        string
+		shouldErr     bool
+	}{
+		{
+			description:  "exact tag",
+			variant:      "ExactTag",
+			expectedName: "test:v1",
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					write("source.go", []byte("code")).
+					add("source.go").
+					commit("initial").
+					tag("v1")
+			},
+		},
+		{
+			description: "exact tag fails on untagged commit",
+			variant:     "ExactTag",
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					write("source.go", []byte("code")).
+					add("source.go").
+					commit("initial")
+			},
+			shouldErr: true,
+		},
+		{
+			description:  "full commit sha",
+			variant:      "CommitSha",
+			expectedName: "test:eefe1b9c44eb0aa87199c9a079f2d48d8eb8baed",
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					write("source.go", []byte("code")).
+					add("source.go").
+					commit("initial").
+					tag("v1")
+			},
+		},
+		{
+			description:  "abbreviated commit sha",
+			variant:      "AbbrevCommitSha",
+			expectedName: "test:eefe1b9",
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					write("source.go", []byte("code")).
+					add("source.go").
+					commit("initial").
+					tag("v1")
+			},
+		},
+		{
+			description:  "tree sha of sub directory",
+			variant:      "TreeSha",
+			expectedName: "test:3bed02ca656e336307e4eb4d80080d7221cba62c",
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					mkdir("artifact1").write("artifact1/source.go", []byte("code")).
+					mkdir("artifact2").write("artifact2/source.go", []byte("code")).
+					add("artifact1/source.go", "artifact2/source.go").
+					commit("initial")
+			},
+			subDir: "artifact1",
+		},
+		{
+			description:  "tree sha ignores commits to other directories",
+			variant:      "AbbrevTreeSha",
+			expectedName: "test:3bed02c",
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					mkdir("artifact1").write("artifact1/source.go", []byte("code")).
+					mkdir("artifact2").write("artifact2/source.go", []byte("code")).
+					add("artifact1/source.go", "artifact2/source.go").
+					commit("initial").
+					write("artifact2/source.go", []byte("updated code")).
+					add("artifact2/source.go").
+					commit("update artifact2")
+			},
+			subDir: "artifact1",
+		},
+		{
+			description:  "branch name",
+			variant:      "BranchName",
+			expectedName: "test:feature-branch",
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					write("source.go", []byte("code")).
+					add("source.go").
+					commit("initial").
+					branch("feature/branch")
+			},
+		},
+		{
+			description:   "dirty with diff",
+			variant:       "AbbrevCommitSha",
+			expectedName:  "test:eefe1b9-dirty-cac8d76",
+			dirtyWithDiff: true,
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					write("source.go", []byte("code")).
+					add("source.go").
+					commit("initial").
+					write("source.go", []byte("updated code"))
+			},
+		},
+		{
+			description:   "dirty with other diff",
+			variant:       "AbbrevCommitSha",
+			expectedName:  "test:eefe1b9-dirty-c370c1b",
+			dirtyWithDiff: true,
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					write("source.go", []byte("code")).
+					add("source.go").
+					commit("initial").
+					write("source.go", []byte("other code"))
+			},
+		},
+		{
+			description:   "dirty with untracked file",
+			expectedName:  "test:eefe1b9-dirty-792d4cf",
+			dirtyWithDiff: true,
+			createGitRepo: func(dir string) {
+				gitInit(t, dir).
+					write("source.go", []byte("code")).
+					add("source.go").
+					commit("initial").
+					write("new.go", []byte("new code"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+
+			tt.createGitRepo(tmpDir.Root())
+			workspace := tmpDir.Path(tt.subDir)
+
+			c, err := NewGitCommit(tt.variant, tt.dirtyWithDiff)
+			testutil.CheckError(t, false, err)
+
+			name, err := c.GenerateFullyQualifiedImageName(workspace, "test")
+
+			testutil.CheckErrorAndDeepEqual(t, tt.shouldErr, err, tt.expectedName, name)
+		})
+	}
+}
+
+func TestNewGitCommit_InvalidVariant(t *testing.T) {
+	_, err := NewGitCommit("Unknown", false)
+
+	testutil.CheckError(t, true, err)
+}
+
 // gitRepo deals with test git repositories
 type gitRepo struct {
 	dir      string
@@ -316,6 +478,16 @@ func (g *gitRepo) tag(tag string) *gitRepo {
 	return g
 }
 
+func (g *gitRepo) branch(name string) *gitRepo {
+	err := g.workTree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.ReferenceName("refs/heads/" + name),
+		Create: true,
+	})
+	failNowIfError(g.t, err)
+
+	return g
+}
+
 func failNowIfError(t *testing.T, err error) {
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ArtifactDependencies lists the source files an artifact is built from.
+type ArtifactDependencies func(ctx context.Context, artifact *latest.Artifact) ([]string, error)
+
+// InputDigestTagger tags an image with a digest of the artifact's inputs:
+// its configuration and the content of its dependencies.
+type InputDigestTagger struct {
+	artifacts    map[string]*latest.Artifact
+	dependencies ArtifactDependencies
+}
+
+// NewInputDigestTagger creates a tagger for the given artifacts.
+func NewInputDigestTagger(artifacts []*latest.Artifact, dependencies ArtifactDependencies) *InputDigestTagger {
+	byName := map[string]*latest.Artifact{}
+	for _, a := range artifacts {
+		byName[a.ImageName] = a
+	}
+
+	return &InputDigestTagger{
+		artifacts:    byName,
+		dependencies: dependencies,
+	}
+}
+
+// Labels are labels specific to the input digest tagger.
+func (t *InputDigestTagger) Labels() map[string]string {
+	return map[string]string{
+		constants.Labels.TagPolicy: "inputDigest",
+	}
+}
+
+// GenerateFullyQualifiedImageName tags an image with the digest of its inputs.
+func (t *InputDigestTagger) GenerateFullyQualifiedImageName(workingDir, imageName string) (string, error) {
+	a, present := t.artifacts[imageName]
+	if !present {
+		return "", fmt.Errorf("unknown artifact %s", imageName)
+	}
+
+	digest, err := t.InputDigest(context.Background(), a)
+	if err != nil {
+		return "", errors.Wrapf(err, "computing input digest of %s", imageName)
+	}
+
+	return fmt.Sprintf("%s:%s", imageName, digest), nil
+}
+
+// InputDigest computes a digest of the artifact's configuration, of the content
+// of all its dependencies and of the inputs of the artifacts it requires.
+func (t *InputDigestTagger) InputDigest(ctx context.Context, a *latest.Artifact) (string, error) {
+	deps, err := t.dependencies(ctx, a)
+	if err != nil {
+		return "", errors.Wrapf(err, "getting dependencies for %s", a.ImageName)
+	}
+	sort.Strings(deps)
+
+	h := sha256.New()
+
+	config, err := yaml.Marshal(a)
+	if err != nil {
+		return "", errors.Wrapf(err, "marshalling configuration of %s", a.ImageName)
+	}
+	h.Write(config)
+
+	workspace, err := filepath.Abs(a.Workspace)
+	if err != nil {
+		return "", errors.Wrap(err, "getting absolute path of workspace")
+	}
+
+	for _, dep := range deps {
+		// Hash relative paths so that the same sources in another folder give the same digest.
+		path := dep
+		if rel, err := filepath.Rel(workspace, dep); err == nil {
+			path = rel
+		}
+		h.Write([]byte(filepath.ToSlash(path)))
+
+		if err := hashFileContent(h, dep); err != nil {
+			return "", errors.Wrapf(err, "hashing %s", dep)
+		}
+	}
+
+	// A change to a required artifact changes the image built for this one.
+	for _, r := range a.Requires {
+		required, present := t.artifacts[r.ImageName]
+		if !present {
+			continue
+		}
+
+		digest, err := t.InputDigest(ctx, required)
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte(digest))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFileContent(w io.Writer, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
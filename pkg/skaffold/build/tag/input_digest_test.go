@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestInputDigest(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("Dockerfile", "FROM busybox").
+		Write("main.go", "package main")
+
+	artifact := &latest.Artifact{
+		ImageName: "image",
+		Workspace: tmpDir.Root(),
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{},
+		},
+	}
+	dependencies := []string{tmpDir.Path("main.go"), tmpDir.Path("Dockerfile")}
+	tagger := NewInputDigestTagger([]*latest.Artifact{artifact}, func(context.Context, *latest.Artifact) ([]string, error) {
+		return dependencies, nil
+	})
+
+	first, err := tagger.InputDigest(context.Background(), artifact)
+	testutil.CheckError(t, false, err)
+
+	// Same inputs, same digest, whatever the order of dependencies.
+	dependencies = []string{tmpDir.Path("Dockerfile"), tmpDir.Path("main.go")}
+	second, err := tagger.InputDigest(context.Background(), artifact)
+	testutil.CheckErrorAndDeepEqual(t, false, err, first, second)
+
+	// Changing a dependency changes the digest.
+	tmpDir.Write("main.go", "package main\n\nfunc main() {}")
+	third, err := tagger.InputDigest(context.Background(), artifact)
+	testutil.CheckError(t, false, err)
+	if third == first {
+		t.Error("expected a different digest when a dependency changes")
+	}
+
+	// Changing the configuration changes the digest.
+	artifact.DockerArtifact.Target = "target"
+	fourth, err := tagger.InputDigest(context.Background(), artifact)
+	testutil.CheckError(t, false, err)
+	if fourth == third {
+		t.Error("expected a different digest when the configuration changes")
+	}
+
+	// A missing dependency is an error.
+	dependencies = []string{tmpDir.Path("missing.go")}
+	_, err = tagger.InputDigest(context.Background(), artifact)
+	testutil.CheckError(t, true, err)
+}
+
+func TestInputDigestWithRequirements(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("base/Dockerfile", "FROM busybox").
+		Write("app/Dockerfile", "FROM base")
+
+	base := &latest.Artifact{ImageName: "base", Workspace: tmpDir.Path("base")}
+	app := &latest.Artifact{
+		ImageName: "app",
+		Workspace: tmpDir.Path("app"),
+		Requires:  []*latest.ArtifactDependency{{ImageName: "base"}},
+	}
+	tagger := NewInputDigestTagger([]*latest.Artifact{app, base}, func(_ context.Context, a *latest.Artifact) ([]string, error) {
+		return []string{a.Workspace + "/Dockerfile"}, nil
+	})
+
+	before, err := tagger.GenerateFullyQualifiedImageName(".", "app")
+	testutil.CheckError(t, false, err)
+
+	// Changing a required artifact changes the tag.
+	tmpDir.Write("base/Dockerfile", "FROM alpine")
+	after, err := tagger.GenerateFullyQualifiedImageName(".", "app")
+	testutil.CheckError(t, false, err)
+
+	if !strings.HasPrefix(before, "app:") || before == after {
+		t.Errorf("expected different tags, got %s and %s", before, after)
+	}
+
+	_, err = tagger.GenerateFullyQualifiedImageName(".", "unknown")
+	testutil.CheckError(t, true, err)
+}
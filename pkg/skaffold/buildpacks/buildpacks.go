@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buildpacks
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultBuilder is the builder image used by `skaffold init`.
+const DefaultBuilder = "heroku/buildpacks"
+
+// projectFiles are the files that identify a project buildpacks know how to build.
+var projectFiles = map[string]bool{
+	"package.json":     true, // Node.js
+	"requirements.txt": true, // Python
+	"Pipfile":          true, // Python
+	"setup.py":         true, // Python
+	"go.mod":           true, // Go
+	"Gemfile":          true, // Ruby
+}
+
+// IsSupportedProject checks if a file identifies a project
+// that can be built with buildpacks.
+func IsSupportedProject(path string) bool {
+	return projectFiles[filepath.Base(path)]
+}
+
+// GetDependencies finds the sources dependencies for the given buildpacks artifact.
+// All paths are relative to the workspace.
+func GetDependencies(ctx context.Context, workspace string, a *latest.BuildpackArtifact) ([]string, error) {
+	deps := a.Dependencies
+	if deps == nil {
+		deps = &latest.BuildpackDependencies{
+			Paths: []string{"."},
+		}
+	}
+
+	paths, err := util.ListFiles(workspace, deps.Paths, deps.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Debugf("Found dependencies for buildpacks artifact: %v", paths)
+
+	return paths, nil
+}
+
+// GeneratePackArgs generates the arguments to `pack` that build
+// the given artifact.
+func GeneratePackArgs(workspace string, a *latest.BuildpackArtifact, tag string, publish bool) []string {
+	args := []string{"build", tag, "--path", workspace, "--builder", a.Builder}
+
+	if a.RunImage != "" {
+		args = append(args, "--run-image", a.RunImage)
+	}
+	for _, buildpack := range a.Buildpacks {
+		args = append(args, "--buildpack", buildpack)
+	}
+	for _, env := range a.Env {
+		args = append(args, "--env", env)
+	}
+	if publish {
+		args = append(args, "--publish")
+	}
+
+	return args
+}
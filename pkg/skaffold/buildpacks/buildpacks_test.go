@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buildpacks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestGetDependencies(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("package.json", "{}").
+		Write("server.js", "").
+		Write("node_modules/express/index.js", "").
+		Write("docs/README.md", "")
+
+	var tests = []struct {
+		description  string
+		dependencies *latest.BuildpackDependencies
+		expected     []string
+	}{
+		{
+			description: "default to the whole workspace",
+			expected:    []string{"docs/README.md", "node_modules/express/index.js", "package.json", "server.js"},
+		},
+		{
+			description:  "ignore",
+			dependencies: &latest.BuildpackDependencies{Paths: []string{"."}, Ignore: []string{"node_modules", "docs"}},
+			expected:     []string{"package.json", "server.js"},
+		},
+		{
+			description:  "paths",
+			dependencies: &latest.BuildpackDependencies{Paths: []string{"*.js"}},
+			expected:     []string{"server.js"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			deps, err := GetDependencies(context.Background(), tmpDir.Root(), &latest.BuildpackArtifact{
+				Builder:      "heroku/buildpacks",
+				Dependencies: test.dependencies,
+			})
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, deps)
+		})
+	}
+}
+
+func TestGeneratePackArgs(t *testing.T) {
+	var tests = []struct {
+		description string
+		artifact    *latest.BuildpackArtifact
+		publish     bool
+		expected    []string
+	}{
+		{
+			description: "builder only",
+			artifact:    &latest.BuildpackArtifact{Builder: "heroku/buildpacks"},
+			expected:    []string{"build", "img:tag", "--path", "ws", "--builder", "heroku/buildpacks"},
+		},
+		{
+			description: "all options",
+			artifact: &latest.BuildpackArtifact{
+				Builder:    "heroku/buildpacks",
+				RunImage:   "heroku/pack:18",
+				Buildpacks: []string{"heroku/nodejs", "heroku/procfile"},
+				Env:        []string{"NODE_ENV=production"},
+			},
+			publish:  true,
+			expected: []string{"build", "img:tag", "--path", "ws", "--builder", "heroku/buildpacks", "--run-image", "heroku/pack:18", "--buildpack", "heroku/nodejs", "--buildpack", "heroku/procfile", "--env", "NODE_ENV=production", "--publish"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			args := GeneratePackArgs("ws", test.artifact, "img:tag", test.publish)
+
+			testutil.CheckDeepEqual(t, test.expected, args)
+		})
+	}
+}
+
+func TestIsSupportedProject(t *testing.T) {
+	testutil.CheckDeepEqual(t, true, IsSupportedProject("web/package.json"))
+	testutil.CheckDeepEqual(t, true, IsSupportedProject("requirements.txt"))
+	testutil.CheckDeepEqual(t, false, IsSupportedProject("web/Dockerfile"))
+}
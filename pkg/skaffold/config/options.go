@@ -48,6 +48,15 @@ type SkaffoldOptions struct {
 	DefaultRepo       string
 	PreBuiltImages    []string
 	Command           string
+	CacheArtifacts    bool
+	CacheFile         string
+	TestReport        string
+	CacheTests        bool
+	TestCacheFile     string
+
+	// BaseImagePollInterval is the interval, in seconds, between two checks
+	// for new digests of the base images. 0 disables the checks.
+	BaseImagePollInterval int
 }
 
 // Labels returns a map of labels to be applied to all deployed
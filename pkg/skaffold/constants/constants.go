@@ -39,12 +39,16 @@ const (
 	DefaultMinikubeContext         = "minikube"
 	DefaultDockerForDesktopContext = "docker-for-desktop"
 	DefaultDockerDesktopContext    = "docker-desktop"
+	KindContextPrefix              = "kind-"
+	K3dContextPrefix               = "k3d-"
 	GCSBucketSuffix                = "_cloudbuild"
 
 	HelmOverridesFilename = "skaffold-overrides.yaml"
 
 	DefaultKustomizationPath = "."
 
+	DefaultIntegrationTestTimeoutSeconds = 300
+
 	DefaultKanikoImage                  = "gcr.io/kaniko-project/executor:v0.8.0@sha256:32ed8afc3c808d7159a7c1789d46c2abe95c1cb5b7afdd6867e360f0ed952c13"
 	DefaultKanikoSecretName             = "kaniko-secret"
 	DefaultKanikoTimeout                = "20m"
@@ -53,9 +57,15 @@ const (
 	DefaultKanikoEmptyDirMountPath      = "/kaniko/buildcontext"
 	DefaultKanikoDockerConfigSecretName = "docker-cfg"
 	DefaultKanikoDockerConfigPath       = "/kaniko/.docker"
+	DefaultKanikoCacheDirName           = "kaniko-cache"
+	DefaultKanikoCacheDirMountPath      = "/cache"
+	DefaultS3Region                     = "us-east-1"
 
 	DefaultBusyboxImage = "busybox"
 
+	DefaultGoImagePackage   = "."
+	DefaultGoImageBaseImage = "gcr.io/distroless/base"
+
 	UpdateCheckEnvironmentVariable = "SKAFFOLD_UPDATE_CHECK"
 
 	DefaultCloudBuildDockerImage = "gcr.io/cloud-builders/docker"
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Environment variables passed to the build command.
+const (
+	ImageEnv        = "IMAGE"
+	PushImageEnv    = "PUSH_IMAGE"
+	BuildContextEnv = "BUILD_CONTEXT"
+)
+
+// Command creates a command that runs a user supplied command line in the workspace.
+func Command(ctx context.Context, workspace string, commandLine string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", commandLine)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", commandLine)
+	}
+	cmd.Dir = workspace
+
+	return cmd
+}
+
+// BuildEnv gives the environment variables passed to the build command.
+func BuildEnv(workspace string, tag string, pushImages bool) ([]string, error) {
+	buildContext, err := filepath.Abs(workspace)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting absolute path of workspace")
+	}
+
+	return []string{
+		fmt.Sprintf("%s=%s", ImageEnv, tag),
+		fmt.Sprintf("%s=%t", PushImageEnv, pushImages),
+		fmt.Sprintf("%s=%s", BuildContextEnv, buildContext),
+	}, nil
+}
+
+// GetDependencies finds the sources dependencies for the given custom artifact.
+// All paths are relative to the workspace.
+func GetDependencies(ctx context.Context, workspace string, a *latest.CustomArtifact) ([]string, error) {
+	deps := a.Dependencies
+	if deps == nil {
+		deps = &latest.CustomDependencies{
+			Paths: []string{"."},
+		}
+	}
+
+	if deps.Command != "" {
+		cmd := Command(ctx, workspace, deps.Command)
+		stdout, err := util.RunCmdOut(cmd)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing dependencies")
+		}
+
+		return util.NonEmptyLines(stdout), nil
+	}
+
+	paths, err := util.ListFiles(workspace, deps.Paths, deps.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Debugf("Found dependencies for custom artifact: %v", paths)
+
+	return paths, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestGetDependencies(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("build.sh", "").
+		Write("src/main.go", "").
+		Write("src/README.md", "").
+		Write("vendor/lib/lib.go", "")
+
+	var tests = []struct {
+		description  string
+		dependencies *latest.CustomDependencies
+		expected     []string
+	}{
+		{
+			description: "defaults to the whole workspace",
+			expected:    []string{"build.sh", filepath.Join("src", "README.md"), filepath.Join("src", "main.go"), filepath.Join("vendor", "lib", "lib.go")},
+		},
+		{
+			description: "paths",
+			dependencies: &latest.CustomDependencies{
+				Paths: []string{"build.sh", "src"},
+			},
+			expected: []string{"build.sh", filepath.Join("src", "README.md"), filepath.Join("src", "main.go")},
+		},
+		{
+			description: "glob",
+			dependencies: &latest.CustomDependencies{
+				Paths: []string{"src/*.go"},
+			},
+			expected: []string{filepath.Join("src", "main.go")},
+		},
+		{
+			description: "ignore",
+			dependencies: &latest.CustomDependencies{
+				Paths:  []string{"."},
+				Ignore: []string{"vendor", "src/*.md"},
+			},
+			expected: []string{"build.sh", filepath.Join("src", "main.go")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			deps, err := GetDependencies(context.Background(), tmpDir.Root(), &latest.CustomArtifact{
+				BuildCommand: "./build.sh",
+				Dependencies: test.dependencies,
+			})
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, deps)
+		})
+	}
+}
+
+func TestGetDependenciesCommand(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).WithRunOut("sh -c git ls-files", "build.sh\n\nsrc/main.go\n")
+
+	deps, err := GetDependencies(context.Background(), ".", &latest.CustomArtifact{
+		BuildCommand: "./build.sh",
+		Dependencies: &latest.CustomDependencies{
+			Command: "git ls-files",
+		},
+	})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{"build.sh", "src/main.go"}, deps)
+}
+
+func TestBuildEnv(t *testing.T) {
+	env, err := BuildEnv("/workspace", "gcr.io/project/image:tag", true)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{
+		"IMAGE=gcr.io/project/image:tag",
+		"PUSH_IMAGE=true",
+		"BUILD_CONTEXT=" + filepath.FromSlash("/workspace"),
+	}, env)
+}
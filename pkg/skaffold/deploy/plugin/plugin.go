@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/plugin/shared"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+)
+
+// NewPluginDeployer launches the deploy plugin's executable and returns
+// the initialized deployer it serves.
+func NewPluginDeployer(cfg *latest.DeployerPlugin, kubeContext string, opts *config.SkaffoldOptions) (shared.PluginDeployer, error) {
+	// We're a host. Start by launching the plugin process.
+	client := plugin.NewClient(&plugin.ClientConfig{
+		Stderr:          os.Stderr,
+		SyncStderr:      os.Stderr,
+		SyncStdout:      os.Stdout,
+		Managed:         true,
+		HandshakeConfig: shared.Handshake,
+		Plugins:         shared.DeployerPluginMap,
+		Cmd:             exec.Command(cfg.Name),
+	})
+
+	// Connect via RPC
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting via rpc")
+	}
+
+	// Request the plugin
+	raw, err := rpcClient.Dispense(shared.DeployerPluginName)
+	if err != nil {
+		return nil, errors.Wrap(err, "requesting rpc plugin")
+	}
+
+	deployer := raw.(shared.PluginDeployer)
+	deployer.Init(opts, kubeContext, cfg)
+	return deployer, nil
+}
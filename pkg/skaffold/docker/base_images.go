@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/pkg/errors"
+)
+
+// ResolveDigest is overridden for unit testing
+var ResolveDigest = RemoteDigest
+
+// BaseImages lists the images, pulled from a registry, that a Dockerfile's
+// stages are based on. Stages based on previous stages and `scratch` are skipped.
+func BaseImages(workspace string, a *latest.DockerArtifact) ([]string, error) {
+	_, nodes, err := parseDockerfile(workspace, a)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	seen := map[string]bool{}
+	for _, node := range baseImageInstructions(nodes) {
+		image := node.Next.Value
+		if !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+
+	return images, nil
+}
+
+// PinBaseImages returns the content of a Dockerfile where the base images
+// are replaced with their current digest in the registry.
+func PinBaseImages(workspace string, a *latest.DockerArtifact) ([]byte, error) {
+	content, nodes, err := parseDockerfile(workspace, a)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for _, node := range baseImageInstructions(nodes) {
+		image := node.Next.Value
+		if strings.Contains(image, "@") {
+			continue
+		}
+
+		digest, err := ResolveDigest(image)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting digest of base image %s", image)
+		}
+
+		// Multi-line instructions are rewritten on a single line.
+		// Following lines are emptied so that line numbers are preserved.
+		start := node.StartLine - 1
+		for i := start + 1; i < len(lines) && strings.HasSuffix(strings.TrimSpace(lines[i-1]), "\\"); i++ {
+			lines[i] = ""
+		}
+		lines[start] = pinnedFromInstruction(node, image+"@"+digest)
+	}
+
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+func parseDockerfile(workspace string, a *latest.DockerArtifact) ([]byte, []*parser.Node, error) {
+	absDockerfilePath, err := NormalizeDockerfilePath(workspace, a.DockerfilePath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "normalizing dockerfile path")
+	}
+
+	content, err := ioutil.ReadFile(absDockerfilePath)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading dockerfile: %s", absDockerfilePath)
+	}
+
+	res, err := parser.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parsing dockerfile")
+	}
+
+	nodes := res.AST.Children
+	expandBuildArgs(nodes, a.BuildArgs)
+
+	return content, nodes, nil
+}
+
+// baseImageInstructions lists the FROM instructions that reference
+// an image rather than a previous stage or `scratch`.
+func baseImageInstructions(nodes []*parser.Node) []*parser.Node {
+	var instructions []*parser.Node
+	stages := map[string]bool{}
+
+	for _, node := range nodes {
+		if node.Value != command.From {
+			continue
+		}
+
+		from := fromInstruction(node)
+		if !stages[strings.ToLower(from.image)] && strings.ToLower(from.image) != "scratch" {
+			instructions = append(instructions, node)
+		}
+		if from.as != "" {
+			stages[from.as] = true
+		}
+	}
+
+	return instructions
+}
+
+func pinnedFromInstruction(node *parser.Node, image string) string {
+	parts := []string{"FROM"}
+	parts = append(parts, node.Flags...)
+	parts = append(parts, image)
+
+	if from := fromInstruction(node); from.as != "" {
+		parts = append(parts, "AS", from.as)
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestBaseImages(t *testing.T) {
+	var tests = []struct {
+		description string
+		dockerfile  string
+		buildArgs   map[string]*string
+		expected    []string
+	}{
+		{
+			description: "single stage",
+			dockerfile:  copyServerGo,
+			expected:    []string{"ubuntu:14.04"},
+		},
+		{
+			description: "multi stage",
+			dockerfile:  "FROM golang:1.12 AS Builder\nFROM builder AS tests\nFROM gcr.io/distroless/base\nCOPY --from=builder /app /app",
+			expected:    []string{"golang:1.12", "gcr.io/distroless/base"},
+		},
+		{
+			description: "scratch",
+			dockerfile:  "FROM scratch\nADD app /",
+		},
+		{
+			description: "build args",
+			dockerfile:  "ARG BASE=busybox\nFROM $BASE\nFROM busybox",
+			buildArgs:   map[string]*string{"BASE": util.StringPtr("alpine")},
+			expected:    []string{"alpine", "busybox"},
+		},
+		{
+			description: "duplicates",
+			dockerfile:  "FROM busybox AS first\nFROM busybox",
+			expected:    []string{"busybox"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+			tmpDir.Write("Dockerfile", test.dockerfile)
+
+			images, err := BaseImages(tmpDir.Root(), &latest.DockerArtifact{
+				DockerfilePath: "Dockerfile",
+				BuildArgs:      test.buildArgs,
+			})
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, images)
+		})
+	}
+}
+
+func TestPinBaseImages(t *testing.T) {
+	defer func(d func(string) (string, error)) { ResolveDigest = d }(ResolveDigest)
+
+	var tests = []struct {
+		description string
+		dockerfile  string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "single stage",
+			dockerfile:  "FROM busybox\nCMD ls",
+			expected:    "FROM busybox@sha256:busybox\nCMD ls",
+		},
+		{
+			description: "multi stage",
+			dockerfile:  "FROM golang:1.12 as builder\nFROM builder AS tests\nFROM --platform=linux/amd64 alpine\nCOPY --from=builder /app /app",
+			expected:    "FROM golang:1.12@sha256:golang:1.12 AS builder\nFROM builder AS tests\nFROM --platform=linux/amd64 alpine@sha256:alpine\nCOPY --from=builder /app /app",
+		},
+		{
+			description: "multi line",
+			dockerfile:  "FROM \\\n  busybox\nCMD ls",
+			expected:    "FROM busybox@sha256:busybox\n\nCMD ls",
+		},
+		{
+			description: "already pinned",
+			dockerfile:  "FROM busybox@sha256:old\nFROM scratch",
+			expected:    "FROM busybox@sha256:old\nFROM scratch",
+		},
+		{
+			description: "unknown image",
+			dockerfile:  "FROM unknown",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ResolveDigest = func(image string) (string, error) {
+				if image == "unknown" {
+					return "", errors.New("not found")
+				}
+				return "sha256:" + image, nil
+			}
+
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+			tmpDir.Write("Dockerfile", test.dockerfile)
+
+			pinned, err := PinBaseImages(tmpDir.Root(), &latest.DockerArtifact{DockerfilePath: "Dockerfile"})
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, string(pinned))
+		})
+	}
+}
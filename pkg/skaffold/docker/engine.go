@@ -0,0 +1,219 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/docker/docker/api/types"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Local container engines.
+const (
+	DockerEngine  = "docker"
+	PodmanEngine  = "podman"
+	BuildahEngine = "buildah"
+)
+
+// NewLocalEngine returns the LocalDaemon used to build and store images
+// with the given container engine. Docker is the default.
+func NewLocalEngine(engine string) (LocalDaemon, error) {
+	switch engine {
+	case "", DockerEngine:
+		return NewAPIClient()
+	case PodmanEngine, BuildahEngine:
+		return &cliEngine{name: engine}, nil
+	default:
+		return nil, fmt.Errorf("unsupported container engine: %s", engine)
+	}
+}
+
+// cliEngine is a LocalDaemon that runs a daemonless container engine's
+// command line interface, like `podman` or `buildah`.
+type cliEngine struct {
+	name       string
+	imageCache sync.Map
+}
+
+// Close is a no-op since there's no connection to a daemon.
+func (e *cliEngine) Close() error {
+	return nil
+}
+
+// ExtraEnv returns no env variables since there's no daemon to point at.
+func (e *cliEngine) ExtraEnv() []string {
+	return nil
+}
+
+// ServerVersion always fails since there's no Docker API.
+func (e *cliEngine) ServerVersion(ctx context.Context) (types.Version, error) {
+	return types.Version{}, fmt.Errorf("%s doesn't expose a Docker API", e.name)
+}
+
+// ConfigFile retrieves and caches image configurations from the registry.
+func (e *cliEngine) ConfigFile(ctx context.Context, image string) (*v1.ConfigFile, error) {
+	cachedCfg, present := e.imageCache.Load(image)
+	if present {
+		return cachedCfg.(*v1.ConfigFile), nil
+	}
+
+	cfg, err := retrieveRemoteConfig(image)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting remote config")
+	}
+
+	e.imageCache.Store(image, cfg)
+
+	return cfg, nil
+}
+
+// Build runs `buildah bud` or `podman build` and returns the imageID.
+// `cacheFrom` images are not passed to the build. They are expected
+// to have been pulled beforehand so that their layers can be reused.
+func (e *cliEngine) Build(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, ref string) (string, error) {
+	if UsesBuildKitFeatures(a) {
+		return "", fmt.Errorf("secrets and ssh require BuildKit, which is not supported by %s", e.name)
+	}
+
+	dockerfilePath, err := NormalizeDockerfilePath(workspace, a.DockerfilePath)
+	if err != nil {
+		return "", errors.Wrap(err, "normalizing dockerfile path")
+	}
+
+	noCacheFrom := *a
+	noCacheFrom.CacheFrom = nil
+
+	args := []string{"build"}
+	if e.name == BuildahEngine {
+		args = []string{"bud"}
+	}
+	args = append(args, "--file", dockerfilePath, "-t", ref)
+	args = append(args, GetBuildArgs(&noCacheFrom)...)
+	args = append(args, workspace)
+
+	if err := e.run(ctx, out, args...); err != nil {
+		return "", errors.Wrap(err, "running build")
+	}
+
+	return e.ImageID(ctx, ref)
+}
+
+// Push pushes an image to a registry and returns its digest.
+func (e *cliEngine) Push(ctx context.Context, out io.Writer, ref string) (string, error) {
+	digestFile, err := ioutil.TempFile("", "digest")
+	if err != nil {
+		return "", errors.Wrap(err, "creating digest file")
+	}
+	digestFile.Close()
+	defer os.Remove(digestFile.Name())
+
+	if err := e.run(ctx, out, "push", "--digestfile", digestFile.Name(), ref, "docker://"+ref); err != nil {
+		return "", errors.Wrap(err, "pushing image to repository")
+	}
+
+	digest, err := ioutil.ReadFile(digestFile.Name())
+	if err != nil || len(digest) == 0 {
+		// Fall back to asking the registry.
+		return RemoteDigest(ref)
+	}
+
+	return strings.TrimSpace(string(digest)), nil
+}
+
+// Pull pulls an image reference from a registry.
+func (e *cliEngine) Pull(ctx context.Context, out io.Writer, ref string) error {
+	return e.run(ctx, out, "pull", ref)
+}
+
+// Load loads an image from a tar file. Returns the imageID for the loaded image.
+func (e *cliEngine) Load(ctx context.Context, out io.Writer, input io.Reader, ref string) (string, error) {
+	tarFile, err := ioutil.TempFile("", "image")
+	if err != nil {
+		return "", errors.Wrap(err, "creating image tarball")
+	}
+	defer os.Remove(tarFile.Name())
+
+	_, err = io.Copy(tarFile, input)
+	tarFile.Close()
+	if err != nil {
+		return "", errors.Wrap(err, "writing image tarball")
+	}
+
+	args := []string{"load", "--input", tarFile.Name()}
+	if e.name == BuildahEngine {
+		args = []string{"pull", "docker-archive:" + tarFile.Name()}
+	}
+
+	if err := e.run(ctx, out, args...); err != nil {
+		return "", errors.Wrap(err, "loading image")
+	}
+
+	return e.ImageID(ctx, ref)
+}
+
+// Save writes an image to a tar file, in the `docker save` format.
+func (e *cliEngine) Save(ctx context.Context, ref, path string) error {
+	args := []string{"save", "--format", "docker-archive", "--output", path, ref}
+	if e.name == BuildahEngine {
+		args = []string{"push", ref, "docker-archive:" + path}
+	}
+
+	return e.run(ctx, ioutil.Discard, args...)
+}
+
+// Tag adds a tag to an image.
+func (e *cliEngine) Tag(ctx context.Context, image, ref string) error {
+	return e.run(ctx, ioutil.Discard, "tag", image, ref)
+}
+
+// ImageID returns the image ID for a corresponding reference.
+// An empty ID is returned if the image is not found.
+func (e *cliEngine) ImageID(ctx context.Context, ref string) (string, error) {
+	args := []string{"image", "inspect", "--format", "{{.Id}}", ref}
+	if e.name == BuildahEngine {
+		args = []string{"inspect", "--type", "image", "--format", "{{.FromImageID}}", ref}
+	}
+
+	cmd := exec.CommandContext(ctx, e.name, args...)
+	out, err := util.RunCmdOut(cmd)
+	if err != nil {
+		logrus.Debugf("Unable to inspect image %s: %s", ref, err)
+		return "", nil
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (e *cliEngine) run(ctx context.Context, out io.Writer, args ...string) error {
+	cmd := exec.CommandContext(ctx, e.name, args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	return util.RunCmd(cmd)
+}
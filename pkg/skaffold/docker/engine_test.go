@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestNewLocalEngine(t *testing.T) {
+	_, err := NewLocalEngine("rkt")
+	testutil.CheckError(t, true, err)
+
+	engine, err := NewLocalEngine(PodmanEngine)
+	testutil.CheckError(t, false, err)
+	if _, ok := engine.(*cliEngine); !ok {
+		t.Errorf("expected a cli engine, got %T", engine)
+	}
+}
+
+func TestCLIEngineBuild(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", "FROM busybox")
+	dockerfile := filepath.Join(tmpDir.Root(), "Dockerfile")
+
+	var tests = []struct {
+		description string
+		engine      string
+		artifact    *latest.DockerArtifact
+		command     util.Command
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "podman",
+			engine:      PodmanEngine,
+			artifact: &latest.DockerArtifact{
+				DockerfilePath: "Dockerfile",
+				BuildArgs:      map[string]*string{"key": util.StringPtr("value")},
+				CacheFrom:      []string{"gcr.io/cache"},
+				Target:         "final",
+			},
+			command: testutil.NewFakeCmd(t).
+				WithRun("podman build --file "+dockerfile+" -t gcr.io/image:tag --build-arg key=value --target final "+tmpDir.Root()).
+				WithRunOut("podman image inspect --format {{.Id}} gcr.io/image:tag", "sha256:podman\n"),
+			expected: "sha256:podman",
+		},
+		{
+			description: "buildah",
+			engine:      BuildahEngine,
+			artifact:    &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+			command: testutil.NewFakeCmd(t).
+				WithRun("buildah bud --file "+dockerfile+" -t gcr.io/image:tag "+tmpDir.Root()).
+				WithRunOut("buildah inspect --type image --format {{.FromImageID}} gcr.io/image:tag", "sha256:buildah"),
+			expected: "sha256:buildah",
+		},
+		{
+			description: "build failure",
+			engine:      PodmanEngine,
+			artifact:    &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+			command: testutil.NewFakeCmd(t).
+				WithRunErr("podman build --file "+dockerfile+" -t gcr.io/image:tag "+tmpDir.Root(), errors.New("BUG")),
+			shouldErr: true,
+		},
+		{
+			description: "secrets are not supported",
+			engine:      BuildahEngine,
+			artifact: &latest.DockerArtifact{
+				DockerfilePath: "Dockerfile",
+				Secrets:        []*latest.DockerSecret{{ID: "npmrc", Src: ".npmrc"}},
+			},
+			command:   testutil.NewFakeCmd(t),
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = test.command
+
+			engine := &cliEngine{name: test.engine}
+			imageID, err := engine.Build(context.Background(), ioutil.Discard, tmpDir.Root(), test.artifact, "gcr.io/image:tag")
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, imageID)
+		})
+	}
+}
+
+func TestCLIEngineImageID(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).
+		WithRun("podman tag gcr.io/image:tag gcr.io/image:other").
+		WithRunOutErr("podman image inspect --format {{.Id}} gcr.io/unknown", "", errors.New("not found"))
+
+	engine := &cliEngine{name: PodmanEngine}
+
+	err := engine.Tag(context.Background(), "gcr.io/image:tag", "gcr.io/image:other")
+	testutil.CheckError(t, false, err)
+
+	imageID, err := engine.ImageID(context.Background(), "gcr.io/unknown")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "", imageID)
+}
+
+func TestCLIEngineSave(t *testing.T) {
+	var tests = []struct {
+		description string
+		engine      string
+		command     util.Command
+	}{
+		{
+			description: "podman",
+			engine:      PodmanEngine,
+			command:     testutil.NewFakeCmd(t).WithRun("podman save --format docker-archive --output image.tar gcr.io/image:tag"),
+		},
+		{
+			description: "buildah",
+			engine:      BuildahEngine,
+			command:     testutil.NewFakeCmd(t).WithRun("buildah push gcr.io/image:tag docker-archive:image.tar"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = test.command
+
+			engine := &cliEngine{name: test.engine}
+			err := engine.Save(context.Background(), "gcr.io/image:tag", "image.tar")
+
+			testutil.CheckError(t, false, err)
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sort"
 	"sync"
 
@@ -46,6 +48,7 @@ type LocalDaemon interface {
 	Push(ctx context.Context, out io.Writer, ref string) (string, error)
 	Pull(ctx context.Context, out io.Writer, ref string) error
 	Load(ctx context.Context, out io.Writer, input io.Reader, ref string) (string, error)
+	Save(ctx context.Context, ref, path string) error
 	Tag(ctx context.Context, image, ref string) error
 	ImageID(ctx context.Context, ref string) (string, error)
 }
@@ -120,6 +123,11 @@ func (l *localDaemon) ConfigFile(ctx context.Context, image string) (*v1.ConfigF
 func (l *localDaemon) Build(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, ref string) (string, error) {
 	logrus.Debugf("Running docker build: context: %s, dockerfile: %s", workspace, a.DockerfilePath)
 
+	if UsesBuildKitFeatures(a) {
+		// The API client can't open the BuildKit session that exposes secrets and ssh.
+		return "", errors.New("secrets and ssh require BuildKit, which is only supported with the docker CLI")
+	}
+
 	// Like `docker build`, we ignore the errors
 	// See https://github.com/docker/cli/blob/75c1bb1f33d7cedbaf48404597d5bf9818199480/cli/command/image/build.go#L364
 	authConfigs, _ := DefaultAuthHelper.GetAllAuthConfigs()
@@ -266,6 +274,27 @@ func (l *localDaemon) Load(ctx context.Context, out io.Writer, input io.Reader,
 	return l.ImageID(ctx, ref)
 }
 
+// Save writes an image to a tar file, in the `docker save` format.
+func (l *localDaemon) Save(ctx context.Context, ref, path string) error {
+	rc, err := l.apiClient.ImageSave(ctx, []string{ref})
+	if err != nil {
+		return errors.Wrap(err, "saving image from docker daemon")
+	}
+	defer rc.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "creating image tarball")
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, rc); err != nil {
+		return errors.Wrap(err, "writing image tarball")
+	}
+
+	return nil
+}
+
 // Tag adds a tag to an image.
 func (l *localDaemon) Tag(ctx context.Context, image, ref string) error {
 	return l.apiClient.ImageTag(ctx, image, ref)
@@ -315,3 +344,37 @@ func GetBuildArgs(a *latest.DockerArtifact) []string {
 
 	return args
 }
+
+// UsesBuildKitFeatures tells if a Docker artifact can only be built with BuildKit.
+func UsesBuildKitFeatures(a *latest.DockerArtifact) bool {
+	return len(a.Secrets) > 0 || len(a.SSH) > 0
+}
+
+// GetBuildKitArgs gives the `docker build` arguments for secrets and ssh,
+// which are only supported by BuildKit.
+func GetBuildKitArgs(workspace string, a *latest.DockerArtifact) ([]string, error) {
+	var args []string
+
+	for _, secret := range a.Secrets {
+		switch {
+		case secret.Src != "" && secret.Env != "":
+			return nil, fmt.Errorf("secret %s can't have both a src and an env", secret.ID)
+		case secret.Src != "":
+			src := secret.Src
+			if !filepath.IsAbs(src) {
+				src = filepath.Join(workspace, src)
+			}
+			args = append(args, "--secret", fmt.Sprintf("id=%s,src=%s", secret.ID, src))
+		case secret.Env != "":
+			args = append(args, "--secret", fmt.Sprintf("id=%s,env=%s", secret.ID, secret.Env))
+		default:
+			return nil, fmt.Errorf("secret %s should have either a src or an env", secret.ID)
+		}
+	}
+
+	for _, ssh := range a.SSH {
+		args = append(args, "--ssh", ssh)
+	}
+
+	return args, nil
+}
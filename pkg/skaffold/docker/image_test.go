@@ -20,6 +20,7 @@ import (
 	"context"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
@@ -168,6 +169,52 @@ func TestImageID(t *testing.T) {
 	}
 }
 
+func TestGetBuildKitArgs(t *testing.T) {
+	tests := []struct {
+		description string
+		artifact    *latest.DockerArtifact
+		want        []string
+		shouldErr   bool
+	}{
+		{
+			description: "secrets",
+			artifact: &latest.DockerArtifact{
+				Secrets: []*latest.DockerSecret{
+					{ID: "npmrc", Src: ".npmrc"},
+					{ID: "key", Src: "/etc/key"},
+					{ID: "token", Env: "TOKEN"},
+				},
+			},
+			want: []string{
+				"--secret", "id=npmrc,src=" + filepath.Join("workspace", ".npmrc"),
+				"--secret", "id=key,src=/etc/key",
+				"--secret", "id=token,env=TOKEN",
+			},
+		},
+		{
+			description: "ssh",
+			artifact: &latest.DockerArtifact{
+				SSH: []string{"default", "github=/home/user/.ssh/id_rsa"},
+			},
+			want: []string{"--ssh", "default", "--ssh", "github=/home/user/.ssh/id_rsa"},
+		},
+		{
+			description: "secret without source",
+			artifact: &latest.DockerArtifact{
+				Secrets: []*latest.DockerSecret{{ID: "npmrc"}},
+			},
+			shouldErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			result, err := GetBuildKitArgs("workspace", tt.artifact)
+
+			testutil.CheckErrorAndDeepEqual(t, tt.shouldErr, err, tt.want, result)
+		})
+	}
+}
+
 func TestGetBuildArgs(t *testing.T) {
 	tests := []struct {
 		description string
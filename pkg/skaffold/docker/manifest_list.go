@@ -0,0 +1,193 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// PlatformImage is an image built for a single platform.
+type PlatformImage struct {
+	// Platform is formatted as `os/arch[/variant]`.
+	Platform string
+
+	// Tag is the reference to the pushed image.
+	Tag string
+}
+
+// for testing
+var (
+	RetrievePlatformImage = RemoteImage
+	WriteManifestList     = pushManifestList
+)
+
+// ParsePlatform parses a platform formatted as `os/arch[/variant]`.
+func ParsePlatform(platform string) (*v1.Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid platform %q, should be os/arch[/variant]", platform)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("invalid platform %q, should be os/arch[/variant]", platform)
+		}
+	}
+
+	p := &v1.Platform{
+		OS:           parts[0],
+		Architecture: parts[1],
+	}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+
+	return p, nil
+}
+
+// PlatformTag returns the tag under which the image built for a given
+// platform is pushed, before being added to a manifest list.
+// For example, `gcr.io/project/image:v1` becomes `gcr.io/project/image:v1-linux-arm64`
+// for the `linux/arm64` platform.
+func PlatformTag(tag, platform string) (string, error) {
+	t, err := name.NewTag(tag, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing tag")
+	}
+
+	suffix := strings.Replace(platform, "/", "-", -1)
+	return fmt.Sprintf("%s:%s-%s", t.Context().Name(), t.TagStr(), suffix), nil
+}
+
+// CreateManifestList assembles the images built for each platform into
+// a manifest list, pushes it with the given tag and returns its digest.
+func CreateManifestList(tag string, images []PlatformImage) (string, error) {
+	list := v1.IndexManifest{
+		SchemaVersion: 2,
+		MediaType:     types.DockerManifestList,
+	}
+
+	for _, image := range images {
+		platform, err := ParsePlatform(image.Platform)
+		if err != nil {
+			return "", err
+		}
+
+		img, err := RetrievePlatformImage(image.Tag)
+		if err != nil {
+			return "", errors.Wrapf(err, "getting image %s", image.Tag)
+		}
+
+		descriptor, err := platformDescriptor(img, platform)
+		if err != nil {
+			return "", errors.Wrapf(err, "describing image %s", image.Tag)
+		}
+
+		// A Docker manifest list can only reference Docker manifests.
+		if descriptor.MediaType != types.DockerManifestSchema2 {
+			list.MediaType = types.OCIImageIndex
+		}
+
+		list.Manifests = append(list.Manifests, *descriptor)
+	}
+
+	raw, err := json.Marshal(list)
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling manifest list")
+	}
+
+	logrus.Debugf("Pushing manifest list %s: %s", tag, raw)
+	if err := WriteManifestList(tag, list.MediaType, raw); err != nil {
+		return "", errors.Wrap(err, "pushing manifest list")
+	}
+
+	digest, _, err := v1.SHA256(bytes.NewReader(raw))
+	if err != nil {
+		return "", errors.Wrap(err, "computing manifest list digest")
+	}
+
+	return digest.String(), nil
+}
+
+func platformDescriptor(img v1.Image, platform *v1.Platform) (*v1.Descriptor, error) {
+	raw, err := img.RawManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting manifest")
+	}
+
+	mediaType, err := img.MediaType()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting media type")
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting digest")
+	}
+
+	return &v1.Descriptor{
+		MediaType: mediaType,
+		Size:      int64(len(raw)),
+		Digest:    digest,
+		Platform:  platform,
+	}, nil
+}
+
+// pushManifestList does a PUT of a manifest list to a registry.
+func pushManifestList(tag string, mediaType types.MediaType, raw []byte) error {
+	ref, err := name.NewTag(tag, name.WeakValidation)
+	if err != nil {
+		return errors.Wrap(err, "parsing tag")
+	}
+
+	auth, err := authn.DefaultKeychain.Resolve(ref.Context().Registry)
+	if err != nil {
+		return errors.Wrap(err, "getting default keychain auth")
+	}
+
+	tr, err := transport.New(ref.Context().Registry, auth, http.DefaultTransport, []string{ref.Scope(transport.PushScope)})
+	if err != nil {
+		return errors.Wrap(err, "creating transport")
+	}
+
+	url := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", ref.Context().Registry.Scheme(), ref.Context().RegistryStr(), ref.Context().RepositoryStr(), ref.Identifier())
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", string(mediaType))
+
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return remote.CheckError(resp, http.StatusOK, http.StatusCreated, http.StatusAccepted)
+}
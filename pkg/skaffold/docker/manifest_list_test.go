@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+type fakePlatformImage struct {
+	v1.Image
+
+	manifest  string
+	mediaType types.MediaType
+}
+
+func (i *fakePlatformImage) RawManifest() ([]byte, error) {
+	return []byte(i.manifest), nil
+}
+
+func (i *fakePlatformImage) MediaType() (types.MediaType, error) {
+	return i.mediaType, nil
+}
+
+func (i *fakePlatformImage) Digest() (v1.Hash, error) {
+	h, _, err := v1.SHA256(bytes.NewReader([]byte(i.manifest)))
+	return h, err
+}
+
+func TestParsePlatform(t *testing.T) {
+	var tests = []struct {
+		platform  string
+		expected  *v1.Platform
+		shouldErr bool
+	}{
+		{platform: "linux/amd64", expected: &v1.Platform{OS: "linux", Architecture: "amd64"}},
+		{platform: "linux/arm/v7", expected: &v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}},
+		{platform: "linux", shouldErr: true},
+		{platform: "linux/", shouldErr: true},
+		{platform: "linux/arm/v7/extra", shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.platform, func(t *testing.T) {
+			platform, err := ParsePlatform(test.platform)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, platform)
+		})
+	}
+}
+
+func TestPlatformTag(t *testing.T) {
+	tag, err := PlatformTag("gcr.io/project/image:v1", "linux/arm/v7")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "gcr.io/project/image:v1-linux-arm-v7", tag)
+
+	tag, err = PlatformTag("localhost:5000/image:latest", "linux/amd64")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "localhost:5000/image:latest-linux-amd64", tag)
+}
+
+func TestCreateManifestList(t *testing.T) {
+	defer func(r func(string) (v1.Image, error)) { RetrievePlatformImage = r }(RetrievePlatformImage)
+	defer func(w func(string, types.MediaType, []byte) error) { WriteManifestList = w }(WriteManifestList)
+
+	var tests = []struct {
+		description       string
+		mediaType         types.MediaType
+		expectedMediaType types.MediaType
+	}{
+		{
+			description:       "docker images",
+			mediaType:         types.DockerManifestSchema2,
+			expectedMediaType: types.DockerManifestList,
+		},
+		{
+			description:       "oci images",
+			mediaType:         types.OCIManifestSchema1,
+			expectedMediaType: types.OCIImageIndex,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			RetrievePlatformImage = func(tag string) (v1.Image, error) {
+				return &fakePlatformImage{manifest: tag, mediaType: test.mediaType}, nil
+			}
+
+			var (
+				pushedTag string
+				pushed    []byte
+				pushedAs  types.MediaType
+			)
+			WriteManifestList = func(tag string, mediaType types.MediaType, raw []byte) error {
+				pushedTag, pushedAs, pushed = tag, mediaType, raw
+				return nil
+			}
+
+			digest, err := CreateManifestList("gcr.io/image:v1", []PlatformImage{
+				{Platform: "linux/amd64", Tag: "gcr.io/image:v1-linux-amd64"},
+				{Platform: "linux/arm64", Tag: "gcr.io/image:v1-linux-arm64"},
+			})
+			testutil.CheckError(t, false, err)
+
+			expectedDigest, _, _ := v1.SHA256(bytes.NewReader(pushed))
+			testutil.CheckDeepEqual(t, expectedDigest.String(), digest)
+			testutil.CheckDeepEqual(t, "gcr.io/image:v1", pushedTag)
+			testutil.CheckDeepEqual(t, test.expectedMediaType, pushedAs)
+
+			var list v1.IndexManifest
+			err = json.Unmarshal(pushed, &list)
+			testutil.CheckError(t, false, err)
+
+			amd64Digest, _ := (&fakePlatformImage{manifest: "gcr.io/image:v1-linux-amd64"}).Digest()
+			testutil.CheckDeepEqual(t, test.expectedMediaType, list.MediaType)
+			testutil.CheckDeepEqual(t, 2, len(list.Manifests))
+			testutil.CheckDeepEqual(t, v1.Descriptor{
+				MediaType: test.mediaType,
+				Size:      int64(len("gcr.io/image:v1-linux-amd64")),
+				Digest:    amd64Digest,
+				Platform:  &v1.Platform{OS: "linux", Architecture: "amd64"},
+			}, list.Manifests[0])
+		})
+	}
+}
@@ -29,7 +29,7 @@ import (
 
 func AddTag(src, target string) error {
 	logrus.Debugf("attempting to add tag %s to src %s", target, src)
-	img, err := remoteImage(src)
+	img, err := RemoteImage(src)
 	if err != nil {
 		return errors.Wrap(err, "getting image")
 	}
@@ -48,7 +48,7 @@ func AddTag(src, target string) error {
 }
 
 func RemoteDigest(identifier string) (string, error) {
-	img, err := remoteImage(identifier)
+	img, err := RemoteImage(identifier)
 	if err != nil {
 		return "", errors.Wrap(err, "getting image")
 	}
@@ -62,7 +62,7 @@ func RemoteDigest(identifier string) (string, error) {
 }
 
 func retrieveRemoteConfig(identifier string) (*v1.ConfigFile, error) {
-	img, err := remoteImage(identifier)
+	img, err := RemoteImage(identifier)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting image")
 	}
@@ -70,7 +70,8 @@ func retrieveRemoteConfig(identifier string) (*v1.ConfigFile, error) {
 	return img.ConfigFile()
 }
 
-func remoteImage(identifier string) (v1.Image, error) {
+// RemoteImage retrieves an image from a registry, using the default keychain.
+func RemoteImage(identifier string) (v1.Image, error) {
 	ref, err := name.ParseReference(identifier, name.WeakValidation)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing initial ref")
@@ -0,0 +1,236 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goimage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Scratch is the name of the empty base image.
+const Scratch = "scratch"
+
+// appDir is the directory of the image where the binary is added.
+const appDir = "/app"
+
+// defaultEnv is the environment used to compile binaries that run in a container.
+var defaultEnv = []string{"CGO_ENABLED=0"}
+
+// defaultPlatform is used when neither the base image nor the artifact's
+// environment give a platform, typically for `scratch`.
+var defaultPlatform = platform{os: "linux", arch: "amd64"}
+
+// platform is the os and architecture the binary is compiled for.
+type platform struct {
+	os   string
+	arch string
+}
+
+func (p platform) String() string {
+	return p.os + "/" + p.arch
+}
+
+// listDepsTemplate prints the source files of the non standard packages
+// a main package depends on.
+const listDepsTemplate = `{{if not .Standard}}{{$dir := .Dir}}{{range .GoFiles}}{{$dir}}/{{.}}
+{{end}}{{range .CgoFiles}}{{$dir}}/{{.}}
+{{end}}{{end}}`
+
+// RetrieveBaseImage is overridden for unit testing
+var RetrieveBaseImage = docker.RemoteImage
+
+// GetDependencies finds the sources dependencies for the given Go artifact,
+// using `go list -deps`. Only the files that are inside the workspace are listed.
+// All paths are relative to the workspace.
+func GetDependencies(ctx context.Context, workspace string, a *latest.GoImageArtifact) ([]string, error) {
+	absWorkspace, err := filepath.Abs(workspace)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting absolute path of workspace")
+	}
+
+	cmd := goCommand(ctx, workspace, a, envPlatform(a.Env), "list", "-deps", "-f", listDepsTemplate, a.Package)
+	stdout, err := util.RunCmdOut(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing go dependencies")
+	}
+
+	deps := map[string]bool{}
+	for _, file := range append(util.NonEmptyLines(stdout), "go.mod", "go.sum") {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(absWorkspace, file)
+		}
+
+		rel, err := filepath.Rel(absWorkspace, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		deps[rel] = true
+	}
+
+	var paths []string
+	for dep := range deps {
+		paths = append(paths, dep)
+	}
+	sort.Strings(paths)
+
+	logrus.Debugf("Found dependencies for go artifact: %v", paths)
+
+	return paths, nil
+}
+
+// Build compiles the main package of an artifact and adds the binary,
+// as the entrypoint, on top of the base image.
+func Build(ctx context.Context, out io.Writer, workspace string, a *latest.GoImageArtifact) (v1.Image, error) {
+	tmpDir, err := ioutil.TempDir("", "skaffold-go-image")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	binaryName, err := binaryName(workspace, a.Package)
+	if err != nil {
+		return nil, err
+	}
+	binary := filepath.Join(tmpDir, binaryName)
+
+	base, err := baseImage(a.BaseImage, envPlatform(a.Env))
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting base image %s", a.BaseImage)
+	}
+
+	p, err := resolvePlatform(base, a.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"build", "-o", binary}
+	args = append(args, a.Flags...)
+	args = append(args, a.Package)
+
+	cmd := goCommand(ctx, workspace, a, p, args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := util.RunCmd(cmd); err != nil {
+		return nil, errors.Wrap(err, "running go build")
+	}
+
+	layer, err := binaryLayer(binary, path.Join(appDir, binaryName))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating layer")
+	}
+
+	return appendLayer(base, layer, path.Join(appDir, binaryName))
+}
+
+func goCommand(ctx context.Context, workspace string, a *latest.GoImageArtifact, p platform, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = workspace
+	cmd.Env = append(os.Environ(), defaultEnv...)
+	cmd.Env = append(cmd.Env, "GOOS="+p.os, "GOARCH="+p.arch)
+	cmd.Env = append(cmd.Env, a.Env...)
+	return cmd
+}
+
+// baseImage retrieves the base image. `scratch` is given the platform
+// set in the artifact's environment since it has none of its own.
+func baseImage(name string, p platform) (v1.Image, error) {
+	if name == Scratch {
+		return emptyImage(p)
+	}
+	return RetrieveBaseImage(name)
+}
+
+// resolvePlatform reads the platform of the base image. `GOOS` and `GOARCH`,
+// if set in the artifact's environment, have to match it.
+func resolvePlatform(base v1.Image, env []string) (platform, error) {
+	config, err := base.ConfigFile()
+	if err != nil {
+		return platform{}, errors.Wrap(err, "getting base image config")
+	}
+
+	p := platform{os: config.OS, arch: config.Architecture}
+	if p.os == "" {
+		p.os = defaultPlatform.os
+	}
+	if p.arch == "" {
+		p.arch = defaultPlatform.arch
+	}
+
+	if goos := envValue(env, "GOOS"); goos != "" && goos != p.os {
+		return platform{}, fmt.Errorf("GOOS=%s doesn't match the base image os %s", goos, p.os)
+	}
+	if goarch := envValue(env, "GOARCH"); goarch != "" && goarch != p.arch {
+		return platform{}, fmt.Errorf("GOARCH=%s doesn't match the base image architecture %s", goarch, p.arch)
+	}
+
+	return p, nil
+}
+
+// envPlatform is the platform set in the artifact's environment,
+// or the default one.
+func envPlatform(env []string) platform {
+	p := defaultPlatform
+	if goos := envValue(env, "GOOS"); goos != "" {
+		p.os = goos
+	}
+	if goarch := envValue(env, "GOARCH"); goarch != "" {
+		p.arch = goarch
+	}
+	return p
+}
+
+// envValue returns the last value given to a key in a `key=value` list.
+func envValue(env []string, key string) string {
+	var value string
+	for _, kv := range env {
+		if strings.HasPrefix(kv, key+"=") {
+			value = strings.TrimPrefix(kv, key+"=")
+		}
+	}
+	return value
+}
+
+// binaryName names the binary after the package being built.
+func binaryName(workspace, pkg string) (string, error) {
+	if strings.HasPrefix(pkg, ".") {
+		absPkg, err := filepath.Abs(filepath.Join(workspace, pkg))
+		if err != nil {
+			return "", errors.Wrap(err, "getting absolute path of package")
+		}
+		return filepath.Base(absPkg), nil
+	}
+
+	return path.Base(pkg), nil
+}
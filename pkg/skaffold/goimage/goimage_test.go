@@ -0,0 +1,204 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goimage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+func TestGetDependencies(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("go.mod", "module example.com/app").
+		Write("main.go", "package main").
+		Write("pkg/lib/lib.go", "package lib")
+
+	output := fmt.Sprintf("%s\n%s\n/go/pkg/mod/github.com/pkg/errors@v0.8.1/errors.go\n", tmpDir.Path("main.go"), tmpDir.Path("pkg/lib/lib.go"))
+	util.DefaultExecCommand = testutil.NewFakeCmd(t).WithRunOut("go list -deps -f "+listDepsTemplate+" .", output)
+
+	deps, err := GetDependencies(context.Background(), tmpDir.Root(), &latest.GoImageArtifact{Package: "."})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{"go.mod", "main.go", "pkg/lib/lib.go"}, deps)
+}
+
+func TestBinaryName(t *testing.T) {
+	name, err := binaryName("/src/app", ".")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "app", name)
+
+	name, err = binaryName("/src/app", "./cmd/server")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "server", name)
+
+	name, err = binaryName("/src/app", "example.com/app/cmd/worker")
+	testutil.CheckErrorAndDeepEqual(t, false, err, "worker", name)
+}
+
+func TestAppendLayer(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("server", "binary content")
+
+	build := func() (string, error) {
+		base, err := baseImage(Scratch, defaultPlatform)
+		if err != nil {
+			return "", err
+		}
+		layer, err := binaryLayer(tmpDir.Path("server"), "/app/server")
+		if err != nil {
+			return "", err
+		}
+		img, err := appendLayer(base, layer, "/app/server")
+		if err != nil {
+			return "", err
+		}
+
+		config, err := img.ConfigFile()
+		if err != nil {
+			return "", err
+		}
+		testutil.CheckDeepEqual(t, []string{"/app/server"}, config.Config.Entrypoint)
+		testutil.CheckDeepEqual(t, 1, len(config.RootFS.DiffIDs))
+
+		manifest, err := img.Manifest()
+		if err != nil {
+			return "", err
+		}
+		testutil.CheckDeepEqual(t, 1, len(manifest.Layers))
+		testutil.CheckDeepEqual(t, types.DockerLayer, manifest.Layers[0].MediaType)
+
+		// The image can be exported to the docker daemon.
+		tag, err := name.NewTag("gcr.io/project/server:tag", name.WeakValidation)
+		if err != nil {
+			return "", err
+		}
+		if err := tarball.Write(tag, img, ioutil.Discard); err != nil {
+			return "", err
+		}
+
+		digest, err := img.Digest()
+		return digest.String(), err
+	}
+
+	first, err := build()
+	testutil.CheckError(t, false, err)
+
+	// Same binary, same image.
+	second, err := build()
+	testutil.CheckErrorAndDeepEqual(t, false, err, first, second)
+}
+
+type ociImage struct {
+	v1.Image
+}
+
+func (i *ociImage) MediaType() (types.MediaType, error) {
+	return types.OCIManifestSchema1, nil
+}
+
+func TestAppendLayerOCI(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("server", "binary content")
+
+	scratch, err := emptyImage(defaultPlatform)
+	testutil.CheckError(t, false, err)
+	layer, err := binaryLayer(tmpDir.Path("server"), "/app/server")
+	testutil.CheckError(t, false, err)
+
+	img, err := appendLayer(&ociImage{Image: scratch}, layer, "/app/server")
+	testutil.CheckError(t, false, err)
+
+	manifest, err := img.Manifest()
+	testutil.CheckErrorAndDeepEqual(t, false, err, types.OCILayer, manifest.Layers[0].MediaType)
+}
+
+func TestResolvePlatform(t *testing.T) {
+	var tests = []struct {
+		description  string
+		basePlatform platform
+		env          []string
+		expected     string
+		shouldErr    bool
+	}{
+		{
+			description:  "scratch",
+			basePlatform: defaultPlatform,
+			expected:     "linux/amd64",
+		},
+		{
+			description:  "arm64 base image",
+			basePlatform: platform{os: "linux", arch: "arm64"},
+			env:          []string{"CGO_ENABLED=1"},
+			expected:     "linux/arm64",
+		},
+		{
+			description:  "matching GOARCH",
+			basePlatform: platform{os: "linux", arch: "arm64"},
+			env:          []string{"GOARCH=arm64"},
+			expected:     "linux/arm64",
+		},
+		{
+			description:  "base image without platform",
+			basePlatform: platform{},
+			expected:     "linux/amd64",
+		},
+		{
+			description:  "GOARCH mismatch",
+			basePlatform: platform{os: "linux", arch: "amd64"},
+			env:          []string{"GOARCH=arm64"},
+			shouldErr:    true,
+		},
+		{
+			description:  "GOOS mismatch",
+			basePlatform: platform{os: "linux", arch: "amd64"},
+			env:          []string{"GOOS=windows"},
+			shouldErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			base, err := emptyImage(test.basePlatform)
+			testutil.CheckError(t, false, err)
+
+			p, err := resolvePlatform(base, test.env)
+
+			if test.shouldErr {
+				testutil.CheckError(t, true, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, p.String())
+			}
+		})
+	}
+}
+
+func TestEnvPlatform(t *testing.T) {
+	testutil.CheckDeepEqual(t, "linux/amd64", envPlatform(nil).String())
+	testutil.CheckDeepEqual(t, "linux/arm64", envPlatform([]string{"GOARCH=arm", "GOARCH=arm64"}).String())
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package goimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+// binaryLayer creates a layer with a single executable file.
+// Timestamps are zeroed so that the same binary always gives the same layer.
+func binaryLayer(binary, target string) (v1.Layer, error) {
+	content, err := ioutil.ReadFile(binary)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading binary")
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	target = strings.TrimPrefix(target, "/")
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     path.Dir(target) + "/",
+		Typeflag: tar.TypeDir,
+		Mode:     0755,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     target,
+		Typeflag: tar.TypeReg,
+		Mode:     0755,
+		Size:     int64(len(content)),
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	layer := buf.Bytes()
+	return tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(layer)), nil
+	})
+}
+
+// emptyImage is an image without any layer, like `FROM scratch`.
+func emptyImage(p platform) (v1.Image, error) {
+	return partial.UncompressedToImage(&scratchImage{platform: p})
+}
+
+type scratchImage struct {
+	platform platform
+}
+
+func (i *scratchImage) MediaType() (types.MediaType, error) {
+	return types.DockerManifestSchema2, nil
+}
+
+func (i *scratchImage) RawConfigFile() ([]byte, error) {
+	return json.Marshal(&v1.ConfigFile{
+		Architecture: i.platform.arch,
+		OS:           i.platform.os,
+		RootFS: v1.RootFS{
+			Type:    "layers",
+			DiffIDs: []v1.Hash{},
+		},
+	})
+}
+
+func (i *scratchImage) LayerByDiffID(h v1.Hash) (partial.UncompressedLayer, error) {
+	return nil, fmt.Errorf("unknown layer %s", h)
+}
+
+// appendLayer adds a layer on top of an image and sets the entrypoint.
+func appendLayer(base v1.Image, layer v1.Layer, entrypoint string) (v1.Image, error) {
+	config, err := base.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting base image config")
+	}
+	config = config.DeepCopy()
+
+	diffID, err := layer.DiffID()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting layer diffID")
+	}
+	config.RootFS.DiffIDs = append(config.RootFS.DiffIDs, diffID)
+	config.History = append(config.History, v1.History{
+		CreatedBy: "skaffold goImage",
+	})
+	config.Config.Entrypoint = []string{entrypoint}
+	config.Config.Cmd = nil
+	config.ContainerConfig = config.Config
+
+	rawConfig, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling config")
+	}
+	configDigest, configSize, err := v1.SHA256(bytes.NewReader(rawConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := base.Manifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting base image manifest")
+	}
+	manifest = manifest.DeepCopy()
+
+	digest, err := layer.Digest()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting layer digest")
+	}
+	size, err := layer.Size()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting layer size")
+	}
+	mediaType, err := base.MediaType()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting base image media type")
+	}
+
+	manifest.Config.Digest = configDigest
+	manifest.Config.Size = configSize
+	manifest.Layers = append(manifest.Layers, v1.Descriptor{
+		MediaType: layerMediaType(mediaType),
+		Size:      size,
+		Digest:    digest,
+	})
+
+	rawManifest, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling manifest")
+	}
+
+	return partial.CompressedToImage(&appendedImage{
+		base:        base,
+		layer:       layer,
+		layerDigest: digest,
+		rawConfig:   rawConfig,
+		rawManifest: rawManifest,
+	})
+}
+
+// layerMediaType is the type of a gzipped layer in a manifest of the given type,
+// so that Docker layers are never added to an OCI image, and vice versa.
+func layerMediaType(manifestType types.MediaType) types.MediaType {
+	if manifestType == types.OCIManifestSchema1 {
+		return types.OCILayer
+	}
+	return types.DockerLayer
+}
+
+type appendedImage struct {
+	base        v1.Image
+	layer       v1.Layer
+	layerDigest v1.Hash
+	rawConfig   []byte
+	rawManifest []byte
+}
+
+func (i *appendedImage) MediaType() (types.MediaType, error) {
+	return i.base.MediaType()
+}
+
+func (i *appendedImage) RawConfigFile() ([]byte, error) {
+	return i.rawConfig, nil
+}
+
+func (i *appendedImage) RawManifest() ([]byte, error) {
+	return i.rawManifest, nil
+}
+
+func (i *appendedImage) LayerByDigest(h v1.Hash) (partial.CompressedLayer, error) {
+	if h == i.layerDigest {
+		return i.layer, nil
+	}
+	return i.base.LayerByDigest(h)
+}
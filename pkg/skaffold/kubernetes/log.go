@@ -204,6 +204,15 @@ func (a *LogAggregator) streamRequest(ctx context.Context, headerColor color.Col
 	return nil
 }
 
+// Flush waits, up to the given timeout, for the logs of the tracked
+// containers to be fully streamed.
+func (a *LogAggregator) Flush(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for a.trackedContainers.count() > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
 // Mute mutes the logs.
 func (a *LogAggregator) Mute() {
 	atomic.StoreInt32(&a.muted, 1)
@@ -235,6 +244,13 @@ func (t *trackedContainers) add(id string) bool {
 	return alreadyTracked
 }
 
+func (t *trackedContainers) count() int {
+	t.Lock()
+	defer t.Unlock()
+
+	return len(t.ids)
+}
+
 func (t *trackedContainers) remove(id string) {
 	t.Lock()
 	delete(t.ids, id)
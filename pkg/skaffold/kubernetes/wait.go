@@ -24,6 +24,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -168,7 +169,8 @@ func WaitForDeploymentToStabilize(ctx context.Context, c kubernetes.Interface, n
 	return err
 }
 
-// WaitForJobToStabilize waits till the Job has at least one active pod
+// WaitForJobToStabilize waits till the Job has at least one active pod,
+// or has already run one, for short lived Jobs.
 func WaitForJobToStabilize(ctx context.Context, c kubernetes.Interface, ns, name string, timeout time.Duration) error {
 	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
 	defer cancelTimeout()
@@ -178,6 +180,29 @@ func WaitForJobToStabilize(ctx context.Context, c kubernetes.Interface, ns, name
 		if err != nil {
 			return false, nil
 		}
-		return job.Status.Active > 0, nil
+		return job.Status.Active > 0 || job.Status.Succeeded > 0 || job.Status.Failed > 0, nil
+	}, ctx.Done())
+}
+
+// WaitForJobToComplete waits till the Job has succeeded.
+// It fails as soon as the Job has failed.
+func WaitForJobToComplete(ctx context.Context, c kubernetes.Interface, ns, name string, timeout time.Duration) error {
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	defer cancelTimeout()
+
+	return wait.PollImmediateUntil(time.Millisecond*500, func() (bool, error) {
+		job, err := c.BatchV1().Jobs(ns).Get(name, meta_v1.GetOptions{})
+		if err != nil {
+			logrus.Infof("Getting job %s", err)
+			return false, nil
+		}
+
+		for _, condition := range job.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+				return false, fmt.Errorf("job %s failed: %s", name, condition.Message)
+			}
+		}
+
+		return job.Status.Succeeded > 0, nil
 	}, ctx.Done())
 }
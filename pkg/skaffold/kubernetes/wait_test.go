@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/GoogleContainerTools/skaffold/testutil"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -131,3 +132,41 @@ func TestWaitForPodReady(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitForJob(t *testing.T) {
+	var tests = []struct {
+		description string
+		status      batchv1.JobStatus
+		shouldErr   bool
+	}{
+		{
+			description: "job succeeded",
+			status:      batchv1.JobStatus{Succeeded: 1},
+		},
+		{
+			description: "job failed",
+			status: batchv1.JobStatus{
+				Failed: 1,
+				Conditions: []batchv1.JobCondition{{
+					Type:   batchv1.JobFailed,
+					Status: v1.ConditionTrue,
+				}},
+			},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+				Status:     test.status,
+			})
+
+			err := WaitForJobToStabilize(context.Background(), client, "ns", "job", time.Second)
+			testutil.CheckError(t, false, err)
+
+			err = WaitForJobToComplete(context.Background(), client, "ns", "job", time.Second)
+			testutil.CheckError(t, test.shouldErr, err)
+		})
+	}
+}
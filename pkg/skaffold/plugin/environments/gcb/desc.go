@@ -67,6 +67,15 @@ func (b *Builder) buildSteps(artifact *latest.Artifact, tag string) ([]*cloudbui
 	case artifact.JibGradleArtifact != nil:
 		return b.jibGradleBuildSteps(artifact.JibGradleArtifact, tag), nil
 
+	case artifact.CustomArtifact != nil:
+		return nil, errors.New("skaffold can't build a custom artifact with Google Cloud Build")
+
+	case artifact.BuildpackArtifact != nil:
+		return nil, errors.New("skaffold can't build a buildpacks artifact with Google Cloud Build")
+
+	case artifact.GoImageArtifact != nil:
+		return nil, errors.New("skaffold can't build a goImage artifact with Google Cloud Build")
+
 	default:
 		return nil, fmt.Errorf("undefined artifact type: %+v", artifact.ArtifactType)
 	}
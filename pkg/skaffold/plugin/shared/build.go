@@ -19,6 +19,7 @@ package shared
 import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 )
 
@@ -26,3 +27,8 @@ type PluginBuilder interface {
 	Init(opts *config.SkaffoldOptions, env *latest.ExecutionEnvironment)
 	build.Builder
 }
+
+type PluginDeployer interface {
+	Init(opts *config.SkaffoldOptions, kubeContext string, cfg *latest.DeployerPlugin)
+	deploy.Deployer
+}
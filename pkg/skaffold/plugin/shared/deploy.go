@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"io"
+	"net/rpc"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// DeployerRPC is an implementation of an rpc client
+type DeployerRPC struct {
+	client *rpc.Client
+	broker *plugin.MuxBroker
+}
+
+func (d *DeployerRPC) Init(opts *config.SkaffoldOptions, kubeContext string, cfg *latest.DeployerPlugin) {
+	if err := convertDeployerPropertiesToBytes(cfg); err != nil {
+		logrus.Errorf("Unable to convert properties to bytes: %v", err)
+	}
+
+	// We don't expect a response, so we can just use interface{}
+	var resp interface{}
+	args := DeployerInitArgs{
+		Opts:        opts,
+		KubeContext: kubeContext,
+		Config:      cfg,
+	}
+	d.client.Call("Plugin.Init", args, &resp)
+}
+
+func (d *DeployerRPC) Labels() map[string]string {
+	var resp map[string]string
+	err := d.client.Call("Plugin.Labels", new(interface{}), &resp)
+	if err != nil {
+		// Can't return error, so log it instead
+		logrus.Errorf("Unable to get labels from server: %v", err)
+	}
+	return resp
+}
+
+func (d *DeployerRPC) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []deploy.Labeller) error {
+	// Labellers can't cross the rpc boundary, so only their labels are sent.
+	var labels []map[string]string
+	for _, l := range labellers {
+		labels = append(labels, l.Labels())
+	}
+
+	streams, done := hostStreams(ctx, d.broker, out)
+	defer done()
+
+	var resp interface{}
+	args := DeployArgs{
+		Builds:  builds,
+		Labels:  labels,
+		Streams: streams,
+	}
+	return d.client.Call("Plugin.Deploy", args, &resp)
+}
+
+func (d *DeployerRPC) Dependencies() ([]string, error) {
+	var resp []string
+	if err := d.client.Call("Plugin.Dependencies", new(interface{}), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (d *DeployerRPC) Cleanup(ctx context.Context, out io.Writer) error {
+	streams, done := hostStreams(ctx, d.broker, out)
+	defer done()
+
+	var resp interface{}
+	return d.client.Call("Plugin.Cleanup", CleanupArgs{Streams: streams}, &resp)
+}
+
+func convertDeployerPropertiesToBytes(cfg *latest.DeployerPlugin) error {
+	if cfg == nil || cfg.Properties == nil {
+		return nil
+	}
+	data, err := yaml.Marshal(cfg.Properties)
+	if err != nil {
+		return err
+	}
+	cfg.Contents = data
+	cfg.Properties = nil
+	return nil
+}
+
+// DeployerRPCServer is the RPC server that DeployerRPC talks to, conforming to
+// the requirements of net/rpc
+type DeployerRPCServer struct {
+	Impl   PluginDeployer
+	broker *plugin.MuxBroker
+}
+
+func (s *DeployerRPCServer) Init(args DeployerInitArgs, resp *interface{}) error {
+	s.Impl.Init(args.Opts, args.KubeContext, args.Config)
+	return nil
+}
+
+func (s *DeployerRPCServer) Labels(args interface{}, resp *map[string]string) error {
+	*resp = s.Impl.Labels()
+	return nil
+}
+
+func (s *DeployerRPCServer) Deploy(d DeployArgs, resp *interface{}) error {
+	var labellers []deploy.Labeller
+	for _, labels := range d.Labels {
+		labellers = append(labellers, staticLabeller(labels))
+	}
+
+	ctx, out, done, err := pluginStreams(s.broker, d.Streams)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := s.Impl.Deploy(ctx, out, d.Builds, labellers); err != nil {
+		return errors.Wrap(err, "deploying")
+	}
+	return nil
+}
+
+func (s *DeployerRPCServer) Dependencies(args interface{}, resp *[]string) error {
+	dependencies, err := s.Impl.Dependencies()
+	if err != nil {
+		return errors.Wrap(err, "getting dependencies")
+	}
+	*resp = dependencies
+	return nil
+}
+
+func (s *DeployerRPCServer) Cleanup(c CleanupArgs, resp *interface{}) error {
+	ctx, out, done, err := pluginStreams(s.broker, c.Streams)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := s.Impl.Cleanup(ctx, out); err != nil {
+		return errors.Wrap(err, "cleaning up")
+	}
+	return nil
+}
+
+// staticLabeller gives the labels received from the host.
+type staticLabeller map[string]string
+
+func (l staticLabeller) Labels() map[string]string {
+	return l
+}
+
+// DeployerInitArgs are args passed via rpc to the deployer plugin on Init()
+type DeployerInitArgs struct {
+	Opts        *config.SkaffoldOptions
+	KubeContext string
+	Config      *latest.DeployerPlugin
+}
+
+// DeployArgs are the args passed via rpc to the deployer plugin on Deploy().
+// Labels holds the labels of each labeller, in order.
+type DeployArgs struct {
+	Builds  []build.Artifact
+	Labels  []map[string]string
+	Streams Streams
+}
+
+// CleanupArgs are the args passed via rpc to the deployer plugin on Cleanup()
+type CleanupArgs struct {
+	Streams Streams
+}
+
+// DeployerPlugin is the implementation of the hashicorp plugin.Plugin interface
+type DeployerPlugin struct {
+	Impl PluginDeployer
+}
+
+func (p *DeployerPlugin) Server(b *plugin.MuxBroker) (interface{}, error) {
+	return &DeployerRPCServer{Impl: p.Impl, broker: b}, nil
+}
+
+func (DeployerPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &DeployerRPC{client: c, broker: b}, nil
+}
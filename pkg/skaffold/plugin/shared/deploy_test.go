@@ -0,0 +1,130 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	plugin "github.com/hashicorp/go-plugin"
+)
+
+type mockDeployer struct {
+	opts        *config.SkaffoldOptions
+	kubeContext string
+	cfg         *latest.DeployerPlugin
+	builds      []build.Artifact
+	labels      []map[string]string
+	cleanedUp   bool
+	err         error
+}
+
+func (d *mockDeployer) Init(opts *config.SkaffoldOptions, kubeContext string, cfg *latest.DeployerPlugin) {
+	d.opts = opts
+	d.kubeContext = kubeContext
+	d.cfg = cfg
+}
+
+func (d *mockDeployer) Labels() map[string]string {
+	return map[string]string{"deployer": "mock"}
+}
+
+func (d *mockDeployer) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []deploy.Labeller) error {
+	d.builds = builds
+	for _, l := range labellers {
+		d.labels = append(d.labels, l.Labels())
+	}
+	return d.err
+}
+
+func (d *mockDeployer) Dependencies() ([]string, error) {
+	return []string{"deployment.yaml"}, d.err
+}
+
+func (d *mockDeployer) Cleanup(ctx context.Context, out io.Writer) error {
+	d.cleanedUp = true
+	return d.err
+}
+
+type labels map[string]string
+
+func (l labels) Labels() map[string]string { return l }
+
+func dispenseDeployer(t *testing.T, impl PluginDeployer) PluginDeployer {
+	client, _ := plugin.TestPluginRPCConn(t, map[string]plugin.Plugin{
+		DeployerPluginName: &DeployerPlugin{Impl: impl},
+	}, nil)
+
+	raw, err := client.Dispense(DeployerPluginName)
+	if err != nil {
+		t.Fatalf("dispensing plugin: %v", err)
+	}
+
+	return raw.(PluginDeployer)
+}
+
+func TestDeployerRPC(t *testing.T) {
+	impl := &mockDeployer{}
+	deployer := dispenseDeployer(t, impl)
+
+	deployer.Init(&config.SkaffoldOptions{Namespace: "ns"}, "kube-context", &latest.DeployerPlugin{
+		Name:       "my-deployer",
+		Properties: map[string]interface{}{"env": "staging"},
+	})
+	testutil.CheckDeepEqual(t, "ns", impl.opts.Namespace)
+	testutil.CheckDeepEqual(t, "kube-context", impl.kubeContext)
+	testutil.CheckDeepEqual(t, "my-deployer", impl.cfg.Name)
+	testutil.CheckDeepEqual(t, "env: staging\n", string(impl.cfg.Contents))
+
+	testutil.CheckDeepEqual(t, map[string]string{"deployer": "mock"}, deployer.Labels())
+
+	builds := []build.Artifact{{ImageName: "image", Tag: "image:tag"}}
+	err := deployer.Deploy(context.Background(), ioutil.Discard, builds, []deploy.Labeller{
+		labels{"first": "one"},
+		labels{"second": "two"},
+	})
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, builds, impl.builds)
+	testutil.CheckDeepEqual(t, []map[string]string{{"first": "one"}, {"second": "two"}}, impl.labels)
+
+	deps, err := deployer.Dependencies()
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{"deployment.yaml"}, deps)
+
+	err = deployer.Cleanup(context.Background(), ioutil.Discard)
+	testutil.CheckErrorAndDeepEqual(t, false, err, true, impl.cleanedUp)
+}
+
+func TestDeployerRPCErrors(t *testing.T) {
+	deployer := dispenseDeployer(t, &mockDeployer{err: errors.New("BUG")})
+
+	err := deployer.Deploy(context.Background(), ioutil.Discard, nil, nil)
+	testutil.CheckError(t, true, err)
+
+	_, err = deployer.Dependencies()
+	testutil.CheckError(t, true, err)
+
+	err = deployer.Cleanup(context.Background(), ioutil.Discard)
+	testutil.CheckError(t, true, err)
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"io"
 	"net/rpc"
-	"os"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
@@ -35,6 +34,7 @@ import (
 // BuilderRPC is an implementation of an rpc client
 type BuilderRPC struct {
 	client *rpc.Client
+	broker *plugin.MuxBroker
 }
 
 func (b *BuilderRPC) Init(opts *config.SkaffoldOptions, env *latest.ExecutionEnvironment) {
@@ -75,11 +75,14 @@ func (b *BuilderRPC) Build(ctx context.Context, out io.Writer, tags tag.ImageTag
 	if err := convertPropertiesToBytes(artifacts); err != nil {
 		return nil, errors.Wrapf(err, "converting properties to bytes")
 	}
+	streams, done := hostStreams(ctx, b.broker, out)
 	args := BuildArgs{
 		ImageTags: tags,
 		Artifacts: artifacts,
+		Streams:   streams,
 	}
 	err := b.client.Call("Plugin.Build", args, &resp)
+	done()
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +107,8 @@ func convertPropertiesToBytes(artifacts []*latest.Artifact) error {
 // BuilderRPCServer is the RPC server that BuilderRPC talks to, conforming to
 // the requirements of net/rpc
 type BuilderRPCServer struct {
-	Impl PluginBuilder
+	Impl   PluginBuilder
+	broker *plugin.MuxBroker
 }
 
 func (s *BuilderRPCServer) Init(args InitArgs, resp *interface{}) error {
@@ -118,7 +122,13 @@ func (s *BuilderRPCServer) Labels(args interface{}, resp *map[string]string) err
 }
 
 func (s *BuilderRPCServer) Build(b BuildArgs, resp *[]build.Artifact) error {
-	artifacts, err := s.Impl.Build(context.Background(), os.Stdout, b.ImageTags, b.Artifacts)
+	ctx, out, done, err := pluginStreams(s.broker, b.Streams)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	artifacts, err := s.Impl.Build(ctx, out, b.ImageTags, b.Artifacts)
 	if err != nil {
 		return errors.Wrap(err, "building artifacts")
 	}
@@ -150,6 +160,7 @@ type InitArgs struct {
 type BuildArgs struct {
 	tag.ImageTags
 	Artifacts []*latest.Artifact
+	Streams   Streams
 }
 
 // BuilderPlugin is the implementation of the hashicorp plugin.Plugin interface
@@ -157,10 +168,10 @@ type BuilderPlugin struct {
 	Impl PluginBuilder
 }
 
-func (p *BuilderPlugin) Server(*plugin.MuxBroker) (interface{}, error) {
-	return &BuilderRPCServer{Impl: p.Impl}, nil
+func (p *BuilderPlugin) Server(b *plugin.MuxBroker) (interface{}, error) {
+	return &BuilderRPCServer{Impl: p.Impl, broker: b}, nil
 }
 
 func (BuilderPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
-	return &BuilderRPC{client: c}, nil
+	return &BuilderRPC{client: c, broker: b}, nil
 }
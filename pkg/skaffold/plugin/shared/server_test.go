@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	plugin "github.com/hashicorp/go-plugin"
+)
+
+type mockBuilder struct {
+	started chan struct{}
+	block   bool
+}
+
+func (b *mockBuilder) Init(opts *config.SkaffoldOptions, env *latest.ExecutionEnvironment) {}
+
+func (b *mockBuilder) Labels() map[string]string { return nil }
+
+func (b *mockBuilder) DependenciesForArtifact(ctx context.Context, artifact *latest.Artifact) ([]string, error) {
+	return nil, nil
+}
+
+func (b *mockBuilder) Build(ctx context.Context, out io.Writer, tags tag.ImageTags, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	var built []build.Artifact
+	for _, a := range artifacts {
+		fmt.Fprintf(out, "Building %s\n", a.ImageName)
+		built = append(built, build.Artifact{ImageName: a.ImageName, Tag: tags[a.ImageName]})
+	}
+
+	if b.block {
+		close(b.started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	return built, nil
+}
+
+func dispenseBuilder(t *testing.T, impl PluginBuilder) PluginBuilder {
+	client, _ := plugin.TestPluginRPCConn(t, map[string]plugin.Plugin{
+		"mock": &BuilderPlugin{Impl: impl},
+	}, nil)
+
+	raw, err := client.Dispense("mock")
+	if err != nil {
+		t.Fatalf("dispensing plugin: %v", err)
+	}
+
+	return raw.(PluginBuilder)
+}
+
+func TestBuilderRPCStreamsOutput(t *testing.T) {
+	builder := dispenseBuilder(t, &mockBuilder{})
+
+	var out bytes.Buffer
+	built, err := builder.Build(context.Background(), &out, tag.ImageTags{"image1": "image1:tag", "image2": "image2:tag"}, []*latest.Artifact{
+		{ImageName: "image1", BuilderPlugin: &latest.BuilderPlugin{Name: "mock"}},
+		{ImageName: "image2", BuilderPlugin: &latest.BuilderPlugin{Name: "mock"}},
+	})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []build.Artifact{
+		{ImageName: "image1", Tag: "image1:tag"},
+		{ImageName: "image2", Tag: "image2:tag"},
+	}, built)
+	testutil.CheckDeepEqual(t, "Building image1\nBuilding image2\n", out.String())
+}
+
+func TestBuilderRPCCancellation(t *testing.T) {
+	impl := &mockBuilder{started: make(chan struct{}), block: true}
+	builder := dispenseBuilder(t, impl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-impl.started
+		cancel()
+	}()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := builder.Build(ctx, &bytes.Buffer{}, tag.ImageTags{}, []*latest.Artifact{
+			{ImageName: "image", BuilderPlugin: &latest.BuilderPlugin{Name: "mock"}},
+		})
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		testutil.CheckError(t, true, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("plugin build was not cancelled")
+	}
+}
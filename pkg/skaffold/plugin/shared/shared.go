@@ -33,3 +33,12 @@ var PluginMap = map[string]plugin.Plugin{
 	"docker": &BuilderPlugin{},
 	"bazel":  &BuilderPlugin{},
 }
+
+// DeployerPluginName is the name under which deployer plugins serve their
+// implementation, whatever the name of their executable.
+const DeployerPluginName = "deployer"
+
+// DeployerPluginMap is the map of plugins dispensed by a deployer plugin
+var DeployerPluginMap = map[string]plugin.Plugin{
+	DeployerPluginName: &DeployerPlugin{},
+}
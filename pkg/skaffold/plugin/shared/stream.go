@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Streams are the ids of the brokered connections used by a plugin to send
+// its output back to skaffold and to be notified of cancellation.
+type Streams struct {
+	Output uint32
+	Cancel uint32
+}
+
+// hostStreams allocates the streams for a call to a plugin. The output
+// written by the plugin is copied to out. The plugin's context is cancelled
+// when ctx is cancelled. The returned function must be called once the
+// call has returned: it waits for the end of the output and releases the streams.
+func hostStreams(ctx context.Context, broker *plugin.MuxBroker, out io.Writer) (Streams, func()) {
+	streams := Streams{
+		Output: broker.NextId(),
+		Cancel: broker.NextId(),
+	}
+
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+
+		conn, err := broker.Accept(streams.Output)
+		if err != nil {
+			logrus.Debugf("Unable to accept plugin output: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		io.Copy(out, conn)
+	}()
+
+	callDone := make(chan struct{})
+	go func() {
+		conn, err := broker.Accept(streams.Cancel)
+		if err != nil {
+			logrus.Debugf("Unable to accept plugin cancellation stream: %v", err)
+			return
+		}
+
+		// Closing the connection is what cancels the plugin's context.
+		select {
+		case <-ctx.Done():
+		case <-callDone:
+		}
+		conn.Close()
+	}()
+
+	var once sync.Once
+	return streams, func() {
+		once.Do(func() {
+			<-outputDone
+			close(callDone)
+		})
+	}
+}
+
+// pluginStreams dials the streams allocated by the host. It returns a context
+// that is cancelled when the host's context is, and a writer for the output.
+// The returned function must be called once the plugin is done.
+func pluginStreams(broker *plugin.MuxBroker, streams Streams) (context.Context, io.Writer, func(), error) {
+	output, err := broker.Dial(streams.Output)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "dialing output stream")
+	}
+
+	cancelConn, err := broker.Dial(streams.Cancel)
+	if err != nil {
+		output.Close()
+		return nil, nil, nil, errors.Wrap(err, "dialing cancellation stream")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go waitForCancellation(cancelConn, cancel)
+
+	return ctx, output, func() {
+		cancel()
+		output.Close()
+		cancelConn.Close()
+	}, nil
+}
+
+// waitForCancellation cancels the context as soon as the host closes
+// the connection.
+func waitForCancellation(conn net.Conn, cancel context.CancelFunc) {
+	conn.Read(make([]byte, 1))
+	cancel()
+}
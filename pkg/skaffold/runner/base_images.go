@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// baseImagePoller checks, at a given interval, the digests of the images
+// that Docker artifacts are based on.
+// The digests of each artifact are written to a state file so that a change
+// can be picked up by the watcher like any other file change.
+// When builds run against the local Docker daemon, base images that change
+// are also pulled so that the next build uses them.
+type baseImagePoller struct {
+	interval  time.Duration
+	dir       string
+	artifacts []*latest.Artifact
+	pull      func(ctx context.Context, image string) error
+	digests   map[string]map[string]string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newBaseImagePoller(interval time.Duration, artifacts []*latest.Artifact, pullImages bool) (*baseImagePoller, error) {
+	dir, err := ioutil.TempDir("", "skaffold-base-images")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating state directory")
+	}
+
+	p := &baseImagePoller{
+		interval:  interval,
+		dir:       dir,
+		artifacts: artifacts,
+		digests:   map[string]map[string]string{},
+	}
+	if pullImages {
+		p.pull = pullImage
+	}
+
+	return p, nil
+}
+
+// start writes the initial state files and then polls the registries
+// in the background, so that the watcher is never blocked by them.
+func (p *baseImagePoller) start(ctx context.Context) {
+	for _, a := range p.artifacts {
+		p.poll(ctx, a)
+	}
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, a := range p.artifacts {
+					p.poll(ctx, a)
+				}
+			}
+		}
+	}()
+}
+
+// stop stops polling and removes the state files.
+func (p *baseImagePoller) stop() {
+	if p.cancel != nil {
+		p.cancel()
+		<-p.done
+	}
+
+	if err := os.RemoveAll(p.dir); err != nil {
+		logrus.Warnln("Unable to remove base images state:", err)
+	}
+}
+
+// dependencies returns the state file of an artifact.
+func (p *baseImagePoller) dependencies(a *latest.Artifact) ([]string, error) {
+	return []string{p.stateFile(a)}, nil
+}
+
+func (p *baseImagePoller) stateFile(a *latest.Artifact) string {
+	return filepath.Join(p.dir, strings.NewReplacer("/", "_", ":", "_").Replace(a.ImageName))
+}
+
+// poll refreshes the state file of an artifact. Base images that have
+// changed since the last poll are pulled, if possible, before the state
+// file is written. Failing to pull them doesn't prevent the rebuild.
+func (p *baseImagePoller) poll(ctx context.Context, a *latest.Artifact) {
+	images, err := docker.BaseImages(a.Workspace, a.DockerArtifact)
+	if err != nil {
+		logrus.Warnf("Unable to list the base images of %s: %s", a.ImageName, err)
+		return
+	}
+
+	digests := map[string]string{}
+	var state strings.Builder
+	for _, image := range images {
+		digest, err := docker.ResolveDigest(image)
+		if err != nil {
+			logrus.Warnf("Unable to check the base image %s of %s: %s", image, a.ImageName, err)
+			return
+		}
+		digests[image] = digest
+		fmt.Fprintf(&state, "%s@%s\n", image, digest)
+	}
+
+	changed := false
+	for _, image := range images {
+		previous, present := p.digests[a.ImageName][image]
+		if present && previous != digests[image] && p.pull != nil {
+			logrus.Infof("Pulling new version of base image %s", image)
+			if err := p.pull(ctx, image); err != nil {
+				logrus.Warnf("Unable to pull base image %s: %s", image, err)
+			}
+		}
+		changed = changed || !present || previous != digests[image]
+	}
+	p.digests[a.ImageName] = digests
+
+	if _, err := os.Stat(p.stateFile(a)); err == nil && !changed {
+		return
+	}
+
+	logrus.Debugf("Base images of %s: %s", a.ImageName, state.String())
+	if err := ioutil.WriteFile(p.stateFile(a), []byte(state.String()), 0644); err != nil {
+		logrus.Warnln("Unable to write base images state:", err)
+	}
+}
+
+func pullImage(ctx context.Context, image string) error {
+	localDocker, err := docker.NewAPIClient()
+	if err != nil {
+		return err
+	}
+
+	return localDocker.Pull(ctx, ioutil.Discard, image)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestBaseImagePoller(t *testing.T) {
+	defer func(d func(string) (string, error)) { docker.ResolveDigest = d }(docker.ResolveDigest)
+
+	digest := "sha256:first"
+	docker.ResolveDigest = func(string) (string, error) { return digest, nil }
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", "FROM busybox")
+
+	artifact := &latest.Artifact{
+		ImageName: "gcr.io/project/image",
+		Workspace: tmpDir.Root(),
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+		},
+	}
+
+	poller, err := newBaseImagePoller(time.Hour, []*latest.Artifact{artifact}, true)
+	testutil.CheckError(t, false, err)
+
+	var pulled []string
+	poller.pull = func(_ context.Context, image string) error {
+		pulled = append(pulled, image)
+		return nil
+	}
+
+	poller.start(context.Background())
+
+	deps := func() ([]string, error) { return poller.dependencies(artifact) }
+	state, err := watch.Stat(deps)
+	testutil.CheckError(t, false, err)
+	if len(state) != 1 {
+		t.Fatalf("expected one state file, got %v", state)
+	}
+
+	// Same digest
+	poller.poll(context.Background(), artifact)
+	unchanged, err := watch.Stat(deps)
+	testutil.CheckErrorAndDeepEqual(t, false, err, state, unchanged)
+	testutil.CheckDeepEqual(t, []string(nil), pulled)
+
+	// New digest
+	digest = "sha256:second"
+	poller.poll(context.Background(), artifact)
+	changed, err := watch.Stat(deps)
+	testutil.CheckError(t, false, err)
+	for file, modTime := range state {
+		if !changed[file].After(modTime) {
+			t.Errorf("expected %s to be modified", file)
+		}
+	}
+	testutil.CheckDeepEqual(t, []string{"busybox"}, pulled)
+
+	// The state files are removed
+	poller.stop()
+	if _, err := os.Stat(poller.dir); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed", poller.dir)
+	}
+}
+
+func TestBaseImagePollerWithoutPull(t *testing.T) {
+	defer func(d func(string) (string, error)) { docker.ResolveDigest = d }(docker.ResolveDigest)
+
+	digest := "sha256:first"
+	docker.ResolveDigest = func(string) (string, error) { return digest, nil }
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("Dockerfile", "FROM busybox")
+
+	artifact := &latest.Artifact{
+		ImageName: "gcr.io/project/image",
+		Workspace: tmpDir.Root(),
+		ArtifactType: latest.ArtifactType{
+			DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"},
+		},
+	}
+
+	var tests = []struct {
+		description string
+		pull        func(context.Context, string) error
+	}{
+		{description: "no local daemon"},
+		{
+			description: "pull fails",
+			pull:        func(context.Context, string) error { return errors.New("no daemon") },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			digest = "sha256:first"
+			poller, err := newBaseImagePoller(time.Hour, []*latest.Artifact{artifact}, false)
+			testutil.CheckError(t, false, err)
+			defer poller.stop()
+			poller.pull = test.pull
+
+			poller.start(context.Background())
+			deps := func() ([]string, error) { return poller.dependencies(artifact) }
+			state, err := watch.Stat(deps)
+			testutil.CheckError(t, false, err)
+
+			// A new digest changes the state file, even without pulling the image.
+			digest = "sha256:second"
+			poller.poll(context.Background(), artifact)
+			changed, err := watch.Stat(deps)
+			testutil.CheckError(t, false, err)
+			for file, modTime := range state {
+				if !changed[file].After(modTime) {
+					t.Errorf("expected %s to be modified", file)
+				}
+			}
+			testutil.CheckDeepEqual(t, "sha256:second", poller.digests[artifact.ImageName]["busybox"])
+		})
+	}
+}
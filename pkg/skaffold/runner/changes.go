@@ -26,6 +26,7 @@ type changes struct {
 	dirtyArtifacts []*artifactChange
 	needsRebuild   []*latest.Artifact
 	needsResync    []*sync.Item
+	needsRetest    bool
 	needsRedeploy  bool
 	needsReload    bool
 }
@@ -40,9 +41,42 @@ func (c *changes) AddDirtyArtifact(a *latest.Artifact, e watch.Events) {
 }
 
 func (c *changes) AddRebuild(a *latest.Artifact) {
+	for _, r := range c.needsRebuild {
+		if r == a {
+			return
+		}
+	}
 	c.needsRebuild = append(c.needsRebuild, a)
 }
 
+// AddDependents marks for rebuild the artifacts that require,
+// directly or not, an artifact that needs to be rebuilt.
+func (c *changes) AddDependents(artifacts []*latest.Artifact) {
+	rebuild := map[string]bool{}
+	for _, a := range c.needsRebuild {
+		rebuild[a.ImageName] = true
+	}
+
+	for added := true; added; {
+		added = false
+
+		for _, a := range artifacts {
+			if rebuild[a.ImageName] {
+				continue
+			}
+
+			for _, r := range a.Requires {
+				if rebuild[r.ImageName] {
+					c.AddRebuild(a)
+					rebuild[a.ImageName] = true
+					added = true
+					break
+				}
+			}
+		}
+	}
+}
+
 func (c *changes) AddResync(s *sync.Item) {
 	c.needsResync = append(c.needsResync, s)
 }
@@ -52,6 +86,7 @@ func (c *changes) reset() {
 	c.needsRebuild = nil
 	c.needsResync = nil
 
+	c.needsRetest = false
 	c.needsRedeploy = false
 	c.needsReload = false
 }
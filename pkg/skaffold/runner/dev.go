@@ -18,6 +18,7 @@ package runner
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -42,6 +43,14 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, output *config.Output, artifac
 	portForwarder := kubernetes.NewPortForwarder(output.Main, r.imageList, r.namespaces)
 	defer portForwarder.Stop()
 
+	if r.builderStopper != nil {
+		defer func() {
+			if err := r.builderStopper.Stop(context.Background()); err != nil {
+				logrus.Warnln("stopping builder:", err)
+			}
+		}()
+	}
+
 	// Create watcher and register artifacts to build current state of files.
 	changed := changes{}
 	onChange := func() error {
@@ -60,6 +69,7 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, output *config.Output, artifac
 				changed.AddRebuild(a.artifact)
 			}
 		}
+		changed.AddDependents(artifacts)
 
 		switch {
 		case changed.needsReload:
@@ -78,8 +88,19 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, output *config.Output, artifac
 				logrus.Warnln("Skipping deploy due to error:", err)
 				return nil
 			}
+		case changed.needsRetest && !r.opts.SkipTests:
+			if err := r.Test(ctx, output.Main, r.builds); err != nil {
+				logrus.Warnln("Skipping deploy due to test error:", err)
+				return nil
+			}
+			if changed.needsRedeploy {
+				if err := r.DeployAndTest(ctx, output.Main, r.builds); err != nil {
+					logrus.Warnln("Skipping deploy due to error:", err)
+					return nil
+				}
+			}
 		case changed.needsRedeploy:
-			if err := r.Deploy(ctx, output.Main, r.builds); err != nil {
+			if err := r.DeployAndTest(ctx, output.Main, r.builds); err != nil {
 				logrus.Warnln("Skipping deploy due to error:", err)
 				return nil
 			}
@@ -108,7 +129,7 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, output *config.Output, artifac
 	// Watch test configuration
 	if err := r.Watcher.Register(
 		r.TestDependencies,
-		func(watch.Events) { changed.needsRedeploy = true },
+		func(watch.Events) { changed.needsRetest = true },
 	); err != nil {
 		return errors.Wrap(err, "watching test files")
 	}
@@ -129,6 +150,34 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, output *config.Output, artifac
 		return errors.Wrapf(err, "watching skaffold configuration %s", r.opts.ConfigurationFile)
 	}
 
+	// Watch base images
+	if r.opts.BaseImagePollInterval > 0 {
+		var dockerArtifacts []*latest.Artifact
+		for _, artifact := range artifacts {
+			if artifact.DockerArtifact != nil && r.IsTargetImage(artifact) {
+				dockerArtifacts = append(dockerArtifacts, artifact)
+			}
+		}
+
+		poller, err := newBaseImagePoller(time.Duration(r.opts.BaseImagePollInterval)*time.Second, dockerArtifacts, r.localDocker)
+		if err != nil {
+			return errors.Wrap(err, "polling base images")
+		}
+		poller.start(ctx)
+		defer poller.stop()
+
+		for i := range dockerArtifacts {
+			artifact := dockerArtifacts[i]
+
+			if err := r.Watcher.Register(
+				func() ([]string, error) { return poller.dependencies(artifact) },
+				func(watch.Events) { changed.AddDirtyArtifact(artifact, watch.Events{}) },
+			); err != nil {
+				return errors.Wrapf(err, "watching base images for artifact %s", artifact.ImageName)
+			}
+		}
+	}
+
 	// First run
 	if err := r.buildTestDeploy(ctx, output.Main, artifacts); err != nil {
 		return errors.Wrap(err, "exiting dev mode because first run failed")
@@ -24,9 +24,17 @@ import (
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	pkgkubernetes "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/integration"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 	"github.com/GoogleContainerTools/skaffold/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 type NoopWatcher struct{}
@@ -70,6 +78,8 @@ func (t *TestWatcher) Run(ctx context.Context, out io.Writer, onChange func() er
 				t.callbacks[0](evt) // 1st artifact changed
 			case "file2":
 				t.callbacks[1](evt) // 2nd artifact changed
+			case "test.yaml":
+				t.callbacks[2](evt) // test configuration changed
 			case "manifest.yaml":
 				t.callbacks[3](evt) // deployment configuration changed
 			}
@@ -258,6 +268,56 @@ func TestDev(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "retest",
+			testBench:   &TestBench{},
+			watchEvents: []watch.Events{
+				{Modified: []string{"test.yaml"}},
+			},
+			expectedActions: []Actions{
+				{
+					Built:    []string{"img1:1", "img2:1"},
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+				{
+					Tested: []string{"img1:1", "img2:1"},
+				},
+			},
+		},
+		{
+			description: "retest and redeploy",
+			testBench:   &TestBench{},
+			watchEvents: []watch.Events{
+				{Modified: []string{"test.yaml", "manifest.yaml"}},
+			},
+			expectedActions: []Actions{
+				{
+					Built:    []string{"img1:1", "img2:1"},
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+				{
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+			},
+		},
+		{
+			description: "ignore retest errors",
+			testBench:   &TestBench{testErrors: []error{nil, errors.New("")}},
+			watchEvents: []watch.Events{
+				{Modified: []string{"test.yaml", "manifest.yaml"}},
+			},
+			expectedActions: []Actions{
+				{
+					Built:    []string{"img1:1", "img2:1"},
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+				{},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -349,3 +409,64 @@ func TestDevSync(t *testing.T) {
 		})
 	}
 }
+
+func TestDevRebuildDependents(t *testing.T) {
+	testBench := &TestBench{}
+	runner := createRunner(t, testBench)
+	runner.Watcher = &TestWatcher{
+		events: []watch.Events{
+			{Modified: []string{"file2"}},
+		},
+		testBench: testBench,
+	}
+
+	err := runner.Dev(context.Background(), discardOutput(), []*latest.Artifact{
+		{ImageName: "img1", Requires: []*latest.ArtifactDependency{{ImageName: "img2"}}},
+		{ImageName: "img2"},
+	})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []Actions{
+		{
+			Built:    []string{"img1:1", "img2:1"},
+			Tested:   []string{"img1:1", "img2:1"},
+			Deployed: []string{"img1:1", "img2:1"},
+		},
+		{
+			Built:    []string{"img2:2", "img1:2"},
+			Tested:   []string{"img2:2", "img1:2"},
+			Deployed: []string{"img2:2", "img1:2"},
+		},
+	}, testBench.Actions())
+}
+
+func TestDevRedeployRunsIntegrationTests(t *testing.T) {
+	jobs := 0
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "jobs", func(k8stesting.Action) (bool, runtime.Object, error) {
+		jobs++
+		return false, nil, nil
+	})
+	// Jobs complete as soon as they are created.
+	client.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.GetAction).GetName()
+		return true, &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: batchv1.JobStatus{Succeeded: 1}}, nil
+	})
+
+	defer func(c func() (kubernetes.Interface, error)) { pkgkubernetes.Client = c }(pkgkubernetes.Client)
+	pkgkubernetes.Client = func() (kubernetes.Interface, error) { return client, nil }
+
+	testBench := &TestBench{}
+	runner := createRunner(t, testBench)
+	runner.integrationTests = integration.NewRunner([]*latest.IntegrationTest{{Name: "check", Image: "busybox", TimeoutSeconds: 5}}, "ns", ".")
+	runner.Watcher = &TestWatcher{
+		events:    []watch.Events{{Modified: []string{"manifest.yaml"}}},
+		testBench: testBench,
+	}
+
+	err := runner.Dev(context.Background(), discardOutput(), []*latest.Artifact{
+		{ImageName: "img1"},
+		{ImageName: "img2"},
+	})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, 2, jobs)
+}
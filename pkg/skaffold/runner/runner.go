@@ -27,6 +27,7 @@ import (
 
 	configutil "github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/cmd/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/cache"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/kaniko"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/local"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/plugin"
@@ -34,6 +35,8 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	deployplugin "github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/plugin"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/plugin/environments/gcb"
@@ -41,6 +44,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/integration"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 )
 
@@ -53,12 +57,17 @@ type SkaffoldRunner struct {
 	sync.Syncer
 	watch.Watcher
 
-	opts        *config.SkaffoldOptions
-	labellers   []deploy.Labeller
-	builds      []build.Artifact
-	hasDeployed bool
-	imageList   *kubernetes.ImageList
-	namespaces  []string
+	opts           *config.SkaffoldOptions
+	labellers      []deploy.Labeller
+	builderStopper build.Stopper
+	builds         []build.Artifact
+	hasDeployed    bool
+	imageList      *kubernetes.ImageList
+	namespaces     []string
+	// localDocker is true when images are built by the local Docker daemon
+	localDocker bool
+
+	integrationTests *integration.Runner
 }
 
 // NewForConfig returns a new SkaffoldRunner for a SkaffoldPipeline
@@ -79,14 +88,17 @@ func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldPipeline) (*
 		return nil, errors.Wrap(err, "getting default repo")
 	}
 
-	tagger, err := getTagger(cfg.Build.TagPolicy, opts.CustomTag)
+	builder, err := getBuilder(&cfg.Build, kubeContext, opts)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing tag config")
+		return nil, errors.Wrap(err, "parsing build config")
 	}
 
-	builder, err := getBuilder(&cfg.Build, kubeContext, opts)
+	// Keep a reference to the builder before it gets wrapped
+	builderStopper, _ := builder.(build.Stopper)
+
+	tagger, err := getTagger(cfg.Build.TagPolicy, opts.CustomTag, cfg.Build.Artifacts, builder.DependenciesForArtifact)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing build config")
+		return nil, errors.Wrap(err, "parsing tag config")
 	}
 
 	tester, err := getTester(cfg.Test, opts)
@@ -94,11 +106,18 @@ func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldPipeline) (*
 		return nil, errors.Wrap(err, "parsing test config")
 	}
 
-	deployer, err := getDeployer(&cfg.Deploy, kubeContext, opts.Namespace, defaultRepo)
+	deployer, err := getDeployer(&cfg.Deploy, kubeContext, opts, defaultRepo)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing deploy config")
 	}
 
+	if opts.CacheArtifacts {
+		builder, err = cache.NewBuilder(builder, cfg.Build.Artifacts, opts.CacheFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating artifact cache")
+		}
+	}
+
 	labellers := []deploy.Labeller{opts, builder, deployer, tagger}
 
 	builder, tester, deployer = WithTimings(builder, tester, deployer)
@@ -111,20 +130,35 @@ func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldPipeline) (*
 		return nil, errors.Wrap(err, "creating watch trigger")
 	}
 
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "finding current directory")
+	}
+	// The first namespace is the one of the current context, or the one on the command line.
+	integrationTests := integration.NewRunner(cfg.Deploy.IntegrationTests, namespaces[0], cwd)
+
 	return &SkaffoldRunner{
-		Builder:    builder,
-		Tester:     tester,
-		Deployer:   deployer,
-		Tagger:     tagger,
-		Syncer:     kubectl.NewSyncer(namespaces),
-		Watcher:    watch.NewWatcher(trigger),
-		opts:       opts,
-		labellers:  labellers,
-		imageList:  kubernetes.NewImageList(),
-		namespaces: namespaces,
+		Builder:        builder,
+		Tester:         tester,
+		Deployer:       deployer,
+		Tagger:         tagger,
+		Syncer:         kubectl.NewSyncer(namespaces),
+		Watcher:        watch.NewWatcher(trigger),
+		opts:           opts,
+		labellers:      labellers,
+		builderStopper: builderStopper,
+		imageList:      kubernetes.NewImageList(),
+		namespaces:     namespaces,
+		localDocker:    usesLocalDocker(cfg.Build),
+
+		integrationTests: integrationTests,
 	}, nil
 }
 
+func usesLocalDocker(cfg latest.BuildConfig) bool {
+	return cfg.LocalBuild != nil && (cfg.LocalBuild.Engine == "" || cfg.LocalBuild.Engine == docker.DockerEngine)
+}
+
 func getBuilder(cfg *latest.BuildConfig, kubeContext string, opts *config.SkaffoldOptions) (build.Builder, error) {
 	switch {
 	case buildWithPlugin(cfg.Artifacts):
@@ -144,7 +178,9 @@ func getBuilder(cfg *latest.BuildConfig, kubeContext string, opts *config.Skaffo
 
 	case cfg.KanikoBuild != nil:
 		logrus.Debugln("Using builder: kaniko")
-		return kaniko.NewBuilder(cfg.KanikoBuild)
+		// Keep a Kaniko pod running between builds only for dev loops
+		reusePod := cfg.KanikoBuild.ReusePod && opts.Command == "dev"
+		return kaniko.NewBuilder(cfg.KanikoBuild, kubeContext, reusePod)
 
 	default:
 		return nil, fmt.Errorf("unknown builder for config %+v", cfg)
@@ -164,13 +200,13 @@ func getTester(cfg []*latest.TestCase, opts *config.SkaffoldOptions) (test.Teste
 	switch {
 	case len(opts.PreBuiltImages) > 0:
 		logrus.Debugln("Skipping tests")
-		return test.NewTester(nil)
+		return test.NewTester(nil, opts)
 	default:
-		return test.NewTester(cfg)
+		return test.NewTester(cfg, opts)
 	}
 }
 
-func getDeployer(cfg *latest.DeployConfig, kubeContext string, namespace string, defaultRepo string) (deploy.Deployer, error) {
+func getDeployer(cfg *latest.DeployConfig, kubeContext string, opts *config.SkaffoldOptions, defaultRepo string) (deploy.Deployer, error) {
 	// TODO(dgageot): this should be the folder containing skaffold.yaml. Should also be moved elsewhere.
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -179,20 +215,23 @@ func getDeployer(cfg *latest.DeployConfig, kubeContext string, namespace string,
 
 	switch {
 	case cfg.HelmDeploy != nil:
-		return deploy.NewHelmDeployer(cfg.HelmDeploy, kubeContext, namespace, defaultRepo), nil
+		return deploy.NewHelmDeployer(cfg.HelmDeploy, kubeContext, opts.Namespace, defaultRepo), nil
 
 	case cfg.KubectlDeploy != nil:
-		return deploy.NewKubectlDeployer(cwd, cfg.KubectlDeploy, kubeContext, namespace, defaultRepo), nil
+		return deploy.NewKubectlDeployer(cwd, cfg.KubectlDeploy, kubeContext, opts.Namespace, defaultRepo), nil
 
 	case cfg.KustomizeDeploy != nil:
-		return deploy.NewKustomizeDeployer(cfg.KustomizeDeploy, kubeContext, namespace, defaultRepo), nil
+		return deploy.NewKustomizeDeployer(cfg.KustomizeDeploy, kubeContext, opts.Namespace, defaultRepo), nil
+
+	case cfg.DeployerPlugin != nil:
+		return deployplugin.NewPluginDeployer(cfg.DeployerPlugin, kubeContext, opts)
 
 	default:
 		return nil, fmt.Errorf("unknown deployer for config %+v", cfg)
 	}
 }
 
-func getTagger(t latest.TagPolicy, customTag string) (tag.Tagger, error) {
+func getTagger(t latest.TagPolicy, customTag string, artifacts []*latest.Artifact, dependencies tag.ArtifactDependencies) (tag.Tagger, error) {
 	switch {
 	case customTag != "":
 		return &tag.CustomTag{
@@ -206,11 +245,14 @@ func getTagger(t latest.TagPolicy, customTag string) (tag.Tagger, error) {
 		return &tag.ChecksumTagger{}, nil
 
 	case t.GitTagger != nil:
-		return &tag.GitCommit{}, nil
+		return tag.NewGitCommit(t.GitTagger.Variant, t.GitTagger.DirtyWithDiff)
 
 	case t.DateTimeTagger != nil:
 		return tag.NewDateTimeTagger(t.DateTimeTagger.Format, t.DateTimeTagger.TimeZone), nil
 
+	case t.InputDigest != nil:
+		return tag.NewInputDigestTagger(artifacts, dependencies), nil
+
 	default:
 		return nil, fmt.Errorf("unknown tagger for strategy %+v", t)
 	}
@@ -244,11 +286,7 @@ func (r *SkaffoldRunner) buildTestDeploy(ctx context.Context, out io.Writer, art
 	// Make sure all artifacts are redeployed. Not only those that were just built.
 	r.builds = mergeWithPreviousBuilds(bRes, r.builds)
 
-	if err := r.Deploy(ctx, out, r.builds); err != nil {
-		return errors.Wrap(err, "deploy failed")
-	}
-
-	return nil
+	return r.DeployAndTest(ctx, out, r.builds)
 }
 
 // Run builds artifacts, runs tests on built artifacts, and then deploys them.
@@ -295,6 +333,14 @@ func (r *SkaffoldRunner) BuildAndTest(ctx context.Context, out io.Writer, artifa
 		return nil, errors.Wrap(err, "generating tag")
 	}
 
+	// Artifacts that are not rebuilt are passed to the artifacts
+	// that require them with the image they were last built to.
+	for _, b := range r.builds {
+		if _, present := tags[b.ImageName]; !present && isRequired(b.ImageName, artifacts) {
+			tags[b.ImageName] = b.Tag
+		}
+	}
+
 	bRes, err := r.Build(ctx, out, tags, artifacts)
 	if err != nil {
 		return nil, errors.Wrap(err, "build failed")
@@ -315,6 +361,22 @@ func (r *SkaffoldRunner) Deploy(ctx context.Context, out io.Writer, artifacts []
 	return err
 }
 
+// DeployAndTest deploys the given artifacts and runs the integration tests
+// against them, unless tests are skipped.
+func (r *SkaffoldRunner) DeployAndTest(ctx context.Context, out io.Writer, artifacts []build.Artifact) error {
+	if err := r.Deploy(ctx, out, artifacts); err != nil {
+		return errors.Wrap(err, "deploy failed")
+	}
+
+	if !r.opts.SkipTests {
+		if err := r.integrationTests.Run(ctx, out, artifacts); err != nil {
+			return errors.Wrap(err, "integration tests failed")
+		}
+	}
+
+	return nil
+}
+
 // TailLogs prints the logs for deployed artifacts.
 func (r *SkaffoldRunner) TailLogs(ctx context.Context, out io.Writer, artifacts []*latest.Artifact, bRes []build.Artifact) error {
 	if !r.opts.Tail {
@@ -334,6 +396,17 @@ func (r *SkaffoldRunner) TailLogs(ctx context.Context, out io.Writer, artifacts
 	return nil
 }
 
+func isRequired(imageName string, artifacts []*latest.Artifact) bool {
+	for _, a := range artifacts {
+		for _, r := range a.Requires {
+			if r.ImageName == imageName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func mergeWithPreviousBuilds(builds, previous []build.Artifact) []build.Artifact {
 	updatedBuilds := map[string]bool{}
 	for _, build := range builds {
@@ -38,6 +38,7 @@ func Set(c *latest.SkaffoldPipeline) error {
 	setDefaultTagger(c)
 	setDefaultKustomizePath(c)
 	setDefaultKubectlManifests(c)
+	setDefaultIntegrationTestTimeouts(c)
 
 	if err := withCloudBuildConfig(c,
 		SetDefaultCloudBuildDockerImage,
@@ -65,6 +66,7 @@ func Set(c *latest.SkaffoldPipeline) error {
 	for _, a := range c.Build.Artifacts {
 		defaultToDockerArtifact(a)
 		setDefaultDockerfile(a)
+		setDefaultGoImage(a)
 		setDefaultWorkspace(a)
 	}
 
@@ -163,6 +165,14 @@ func setDefaultKubectlManifests(c *latest.SkaffoldPipeline) {
 	}
 }
 
+func setDefaultIntegrationTestTimeouts(c *latest.SkaffoldPipeline) {
+	for _, t := range c.Deploy.IntegrationTests {
+		if t.TimeoutSeconds == 0 {
+			t.TimeoutSeconds = constants.DefaultIntegrationTestTimeoutSeconds
+		}
+	}
+}
+
 func defaultToDockerArtifact(a *latest.Artifact) {
 	if a.ArtifactType == (latest.ArtifactType{}) {
 		a.ArtifactType = latest.ArtifactType{
@@ -182,6 +192,13 @@ func SetDefaultDockerArtifact(a *latest.DockerArtifact) {
 	a.DockerfilePath = valueOrDefault(a.DockerfilePath, constants.DefaultDockerfilePath)
 }
 
+func setDefaultGoImage(a *latest.Artifact) {
+	if goImage := a.GoImageArtifact; goImage != nil {
+		goImage.Package = valueOrDefault(goImage.Package, constants.DefaultGoImagePackage)
+		goImage.BaseImage = valueOrDefault(goImage.BaseImage, constants.DefaultGoImageBaseImage)
+	}
+}
+
 func setDefaultWorkspace(a *latest.Artifact) {
 	a.Workspace = valueOrDefault(a.Workspace, ".")
 }
@@ -263,6 +280,10 @@ func setDefaultKanikoBuildContext(kaniko *latest.KanikoBuild) error {
 			LocalDir: &latest.LocalDir{},
 		}
 	}
+
+	if s3 := kaniko.BuildContext.S3Bucket; s3 != nil {
+		s3.Region = valueOrDefault(s3.Region, constants.DefaultS3Region)
+	}
 	return nil
 }
 
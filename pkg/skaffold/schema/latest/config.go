@@ -92,6 +92,18 @@ type BuilderPlugin struct {
 	Contents []byte `yaml:",omitempty"`
 }
 
+// DeployerPlugin contains all fields necessary for specifying a deploy plugin.
+type DeployerPlugin struct {
+	// Name is the name of the deploy plugin's executable.
+	Name string `yaml:"name" yamltags:"required"`
+
+	// Properties are key-value pairs passed to the plugin.
+	Properties map[string]interface{} `yaml:"properties,omitempty"`
+
+	// Contents
+	Contents []byte `yaml:",omitempty"`
+}
+
 // TagPolicy contains all the configuration for the tagging step.
 type TagPolicy struct {
 	// GitTagger (beta) tags images with the git tag or commit of the artifact's workspace.
@@ -105,13 +117,35 @@ type TagPolicy struct {
 
 	// DateTimeTagger (beta) tags images with the build timestamp.
 	DateTimeTagger *DateTimeTagger `yaml:"dateTime,omitempty" yamltags:"oneOf=tag"`
+
+	// InputDigest (alpha) tags images with a digest of the artifact's inputs.
+	InputDigest *InputDigest `yaml:"inputDigest,omitempty" yamltags:"oneOf=tag"`
 }
 
 // ShaTagger (beta) tags images with their sha256 digest.
 type ShaTagger struct{}
 
 // GitTagger (beta) tags images with the git tag or commit of the artifact's workspace.
-type GitTagger struct{}
+type GitTagger struct {
+	// Variant determines the behavior of the git tagger. Valid variants are
+	// `Tags` (default): use the git tag or fall back to the abbreviated commit hash.
+	// `ExactTag`: use the git tag and fail if the commit is not tagged.
+	// `CommitSha`: use the full git commit hash.
+	// `AbbrevCommitSha`: use the abbreviated git commit hash.
+	// `TreeSha`: use the full tree hash of the artifact's workspace.
+	// `AbbrevTreeSha`: use the abbreviated tree hash of the artifact's workspace.
+	// `BranchName`: use the name of the current branch.
+	Variant string `yaml:"variant,omitempty"`
+
+	// DirtyWithDiff appends a hash of the uncommitted changes to the `-dirty` suffix
+	// so that different changes give different tags.
+	DirtyWithDiff bool `yaml:"dirtyWithDiff,omitempty"`
+}
+
+// InputDigest (alpha) tags images with a digest of the artifact's inputs:
+// its configuration, the content of its source dependencies and the inputs
+// of the artifacts it requires. The same sources always give the same tag.
+type InputDigest struct{}
 
 // EnvTemplateTagger (beta) tags images with a configurable template string.
 type EnvTemplateTagger struct {
@@ -161,11 +195,32 @@ type LocalBuild struct {
 	// connects to a remote cluster.
 	Push *bool `yaml:"push,omitempty"`
 
+	// Engine is the container engine used to build Docker artifacts and store
+	// the images locally: `docker`, `podman` or `buildah`.
+	// Podman and Buildah don't require a Docker daemon, only build Docker artifacts
+	// and always push the images.
+	// Defaults to `docker`.
+	Engine string `yaml:"engine,omitempty"`
+
 	// UseDockerCLI use `docker` command-line interface instead of Docker Engine APIs.
 	UseDockerCLI bool `yaml:"useDockerCLI,omitempty"`
 
 	// UseBuildkit use BuildKit to build Docker images.
 	UseBuildkit bool `yaml:"useBuildkit,omitempty"`
+
+	// Concurrency is how many artifacts can be built concurrently. 0 means "no-limit".
+	// Defaults to `1`.
+	Concurrency *int `yaml:"concurrency,omitempty"`
+
+	// FailFast stops all the builds as soon as one of them fails.
+	// When `false`, all the artifacts are built and all the errors are reported.
+	// Defaults to `true`.
+	FailFast *bool `yaml:"failFast,omitempty"`
+
+	// PinBaseImages replaces, at build time, the base images of Docker artifacts
+	// with their current digest in the registry. This forces the build to
+	// use the latest version of each base image. Implies `useDockerCLI: true`.
+	PinBaseImages bool `yaml:"pinBaseImages,omitempty"`
 }
 
 // GoogleCloudBuild (beta) describes how to do a remote build on
@@ -211,6 +266,9 @@ type GoogleCloudBuild struct {
 // LocalDir configures how Kaniko mounts sources directly via an `emptyDir` volume.
 type LocalDir struct{}
 
+// StdinContext configures how Kaniko reads sources streamed to its standard input.
+type StdinContext struct{}
+
 // KanikoBuildContext contains the different fields available to specify
 // a Kaniko build context.
 type KanikoBuildContext struct {
@@ -220,6 +278,43 @@ type KanikoBuildContext struct {
 
 	// LocalDir configures how Kaniko mounts sources directly via an `emptyDir` volume.
 	LocalDir *LocalDir `yaml:"localDir,omitempty" yamltags:"oneOf=buildContext"`
+
+	// Stdin streams the sources to Kaniko's standard input, without requiring
+	// an init container or a bucket.
+	// Requires a Kaniko `image` that supports the `tar://stdin` build context.
+	Stdin *StdinContext `yaml:"stdin,omitempty" yamltags:"oneOf=buildContext"`
+
+	// S3Bucket is the S3-compatible bucket to which sources are uploaded by Skaffold.
+	// Kaniko will need access to that bucket to download the sources.
+	S3Bucket *S3Bucket `yaml:"s3Bucket,omitempty" yamltags:"oneOf=buildContext"`
+}
+
+// S3Bucket describes an S3-compatible bucket, like AWS S3 or MinIO.
+// Skaffold uploads sources with the credentials found in the
+// `AWS_ACCESS_KEY_ID`, `AWS_SECRET_ACCESS_KEY` and `AWS_SESSION_TOKEN` env variables.
+type S3Bucket struct {
+	// Bucket is the name of the bucket.
+	Bucket string `yaml:"bucket" yamltags:"required"`
+
+	// Endpoint is the URL of the S3-compatible service.
+	// Requires a Kaniko `image` that supports the `S3_ENDPOINT` env variable.
+	// Defaults to AWS S3 for the given region.
+	// For example: `http://minio.minio:9000`.
+	Endpoint string `yaml:"endpoint,omitempty"`
+
+	// Region is the region of the bucket.
+	// Defaults to `us-east-1`.
+	Region string `yaml:"region,omitempty"`
+
+	// PathStyle addresses the bucket as `<endpoint>/<bucket>`
+	// instead of `<bucket>.<endpoint>`. MinIO usually requires it.
+	// Requires a Kaniko `image` that supports the `S3_FORCE_PATH_STYLE` env variable.
+	PathStyle bool `yaml:"pathStyle,omitempty"`
+
+	// SecretName is the name of a Kubernetes secret, with `AWS_ACCESS_KEY_ID`
+	// and `AWS_SECRET_ACCESS_KEY` keys, used by Kaniko to download the sources.
+	// If not set, Skaffold copies its own credentials to a temporary secret.
+	SecretName string `yaml:"secretName,omitempty"`
 }
 
 // KanikoCache configures Kaniko caching. If a cache is specified, Kaniko will
@@ -262,12 +357,136 @@ type KanikoBuild struct {
 	Timeout string `yaml:"timeout,omitempty"`
 
 	// Image is the Docker image used by the Kaniko pod.
-	// Defaults to the latest released version of `gcr.io/kaniko-project/executor`.
+	// Defaults to the latest released version of `gcr.io/kaniko-project/executor`,
+	// or its `debug` flavor when `reusePod` is set.
 	Image string `yaml:"image,omitempty"`
 
 	// DockerConfig describes how to mount the local Docker configuration into the
 	// Kaniko pod.
 	DockerConfig *DockerConfig `yaml:"dockerConfig,omitempty"`
+
+	// Concurrency is how many artifacts can be built concurrently. 0 means "no-limit".
+	// Defaults to `0`.
+	Concurrency *int `yaml:"concurrency,omitempty"`
+
+	// FailFast stops all the builds as soon as one of them fails.
+	// When `false`, all the artifacts are built and all the errors are reported.
+	// Defaults to `true`.
+	FailFast *bool `yaml:"failFast,omitempty"`
+
+	// PodTemplate (alpha) describes overrides applied to the pod that runs Kaniko.
+	PodTemplate *KanikoPodTemplate `yaml:"podTemplate,omitempty"`
+
+	// ReusePod (alpha) keeps a Kaniko pod running during `skaffold dev` and
+	// uses it for successive builds, instead of creating a pod for each build.
+	// Requires a `localDir` build context and an `image` that contains a shell
+	// and Kaniko v0.9.0 or later, such as the debug executor.
+	// Defaults to `false`.
+	ReusePod bool `yaml:"reusePod,omitempty"`
+}
+
+// KanikoPodTemplate describes overrides that are merged into the pod that runs Kaniko.
+type KanikoPodTemplate struct {
+	// Annotations are added to the pod.
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+
+	// Resources are the compute resources of the Kaniko container.
+	Resources *ResourceRequirements `yaml:"resources,omitempty"`
+
+	// NodeSelector constrains the nodes the pod can be scheduled on.
+	// For example: `{"cloud.google.com/gke-nodepool": "builds"}`.
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+
+	// Tolerations allow the pod to be scheduled on nodes with matching taints.
+	Tolerations []*Toleration `yaml:"tolerations,omitempty"`
+
+	// ServiceAccountName is the name of the Kubernetes service account used to run the pod.
+	ServiceAccountName string `yaml:"serviceAccountName,omitempty"`
+
+	// Env are environment variables, in the `key=value` form, set on the Kaniko container.
+	Env []string `yaml:"env,omitempty"`
+
+	// Volumes are additional volumes added to the pod.
+	Volumes []*KanikoVolume `yaml:"volumes,omitempty"`
+
+	// VolumeMounts are additional volumes mounted into the Kaniko container.
+	VolumeMounts []*KanikoVolumeMount `yaml:"volumeMounts,omitempty"`
+}
+
+// ResourceRequirements describes the compute resources of a container.
+type ResourceRequirements struct {
+	// Requests are the minimum amount of compute resources required.
+	Requests *ResourceList `yaml:"requests,omitempty"`
+
+	// Limits are the maximum amount of compute resources allowed.
+	Limits *ResourceList `yaml:"limits,omitempty"`
+}
+
+// ResourceList lists amounts of compute resources, as Kubernetes quantities.
+type ResourceList struct {
+	// CPU is an amount of cpu. For example: `500m` or `2`.
+	CPU string `yaml:"cpu,omitempty"`
+
+	// Memory is an amount of memory. For example: `1Gi`.
+	Memory string `yaml:"memory,omitempty"`
+
+	// EphemeralStorage is an amount of local ephemeral storage. For example: `10Gi`.
+	EphemeralStorage string `yaml:"ephemeralStorage,omitempty"`
+}
+
+// Toleration allows a pod to be scheduled on nodes with a matching taint.
+type Toleration struct {
+	// Key is the taint key that the toleration applies to.
+	// Empty means match all taint keys.
+	Key string `yaml:"key,omitempty"`
+
+	// Operator is either `Exists` or `Equal`.
+	// Defaults to `Equal`.
+	Operator string `yaml:"operator,omitempty"`
+
+	// Value is the taint value that the toleration matches.
+	Value string `yaml:"value,omitempty"`
+
+	// Effect is the taint effect to match: `NoSchedule`, `PreferNoSchedule` or `NoExecute`.
+	// Empty means match all taint effects.
+	Effect string `yaml:"effect,omitempty"`
+
+	// TolerationSeconds is how long a pod tolerates a `NoExecute` taint.
+	TolerationSeconds *int64 `yaml:"tolerationSeconds,omitempty"`
+}
+
+// KanikoVolume is a volume added to the Kaniko pod.
+// Only one source should be set.
+type KanikoVolume struct {
+	// Name is the name of the volume, referenced by volume mounts.
+	Name string `yaml:"name" yamltags:"required"`
+
+	// Secret is the name of a Kubernetes secret.
+	Secret string `yaml:"secret,omitempty"`
+
+	// ConfigMap is the name of a Kubernetes config map.
+	ConfigMap string `yaml:"configMap,omitempty"`
+
+	// HostPath is a path on the node.
+	HostPath string `yaml:"hostPath,omitempty"`
+
+	// PersistentVolumeClaim is the name of a persistent volume claim.
+	PersistentVolumeClaim string `yaml:"persistentVolumeClaim,omitempty"`
+
+	// EmptyDir uses an empty directory that lives as long as the pod.
+	EmptyDir bool `yaml:"emptyDir,omitempty"`
+}
+
+// KanikoVolumeMount describes where a volume is mounted into the Kaniko container.
+type KanikoVolumeMount struct {
+	// Name is the name of the volume.
+	Name string `yaml:"name" yamltags:"required"`
+
+	// MountPath is the path where the volume is mounted.
+	MountPath string `yaml:"mountPath" yamltags:"required"`
+
+	// ReadOnly mounts the volume read-only.
+	ReadOnly bool `yaml:"readOnly,omitempty"`
 }
 
 // DockerConfig contains information about the docker `config.json` to mount.
@@ -289,11 +508,68 @@ type TestCase struct {
 	// to run on that artifact.
 	// For example: `["./test/*"]`.
 	StructureTests []string `yaml:"structureTests,omitempty"`
+
+	// CustomTests (alpha) lists the commands to run on that artifact.
+	CustomTests []*CustomTest `yaml:"custom,omitempty"`
+}
+
+// CustomTest (alpha) is a user supplied command that tests an image.
+type CustomTest struct {
+	// Command is the command to run. The fully qualified name of the
+	// image to test, including its tag, is in the `IMAGE` environment variable.
+	// For example: `docker run --rm $IMAGE go test ./...`.
+	Command string `yaml:"command" yamltags:"required"`
+
+	// Dir is the directory in which the command runs.
+	// Defaults to the current directory.
+	Dir string `yaml:"dir,omitempty"`
+
+	// TimeoutSeconds is the maximum time, in seconds, the command can run.
+	// Defaults to no timeout.
+	TimeoutSeconds int `yaml:"timeoutSeconds,omitempty"`
+
+	// Dependencies lists the files or glob patterns that trigger a new run
+	// of the tests when modified, in `skaffold dev`.
+	// For example: `["test", "go.mod"]`.
+	Dependencies []string `yaml:"dependencies,omitempty"`
 }
 
 // DeployConfig contains all the configuration needed by the deploy steps.
 type DeployConfig struct {
 	DeployType `yaml:",inline"`
+
+	// IntegrationTests (alpha) lists the Kubernetes Jobs to run after each
+	// successful deployment. A Job that fails makes the pipeline fail.
+	IntegrationTests []*IntegrationTest `yaml:"integrationTests,omitempty"`
+}
+
+// IntegrationTest (alpha) is a Kubernetes Job run after a deployment,
+// either read from a manifest or created from an image and a command.
+// Only one of `manifest` or `image` should be set.
+type IntegrationTest struct {
+	// Name is the prefix of the Job's name. A random suffix is appended.
+	// Required with `image`. Defaults to the name in the manifest.
+	Name string `yaml:"name,omitempty"`
+
+	// Manifest is the path to a Job manifest. Images of the built artifacts
+	// are replaced by their tag.
+	// For example: `k8s/smoke-test-job.yaml`.
+	Manifest string `yaml:"manifest,omitempty" yamltags:"oneOf=job"`
+
+	// Image is the image of the Job's only container. It can be one of the built artifacts.
+	// For example: `gcr.io/k8s-skaffold/smoke-tests`.
+	Image string `yaml:"image,omitempty" yamltags:"oneOf=job"`
+
+	// Command overrides the entrypoint of the image.
+	// For example: `["./smoke-test.sh"]`.
+	Command []string `yaml:"command,omitempty"`
+
+	// Args are the arguments passed to the command.
+	Args []string `yaml:"args,omitempty"`
+
+	// TimeoutSeconds is the maximum time, in seconds, to wait for the Job to complete.
+	// Defaults to `300`.
+	TimeoutSeconds int `yaml:"timeoutSeconds,omitempty"`
 }
 
 // DeployType contains the specific implementation and parameters needed
@@ -308,6 +584,10 @@ type DeployType struct {
 
 	// KustomizeDeploy (beta) uses the `kustomize` CLI to "patch" a deployment for a target environment.
 	KustomizeDeploy *KustomizeDeploy `yaml:"kustomize,omitempty" yamltags:"oneOf=deploy"`
+
+	// DeployerPlugin (alpha) delegates the deployment to an external plugin
+	// speaking the go-plugin RPC protocol.
+	DeployerPlugin *DeployerPlugin `yaml:"plugin,omitempty" yamltags:"oneOf=deploy"`
 }
 
 // KubectlDeploy (beta) uses a client side `kubectl apply` to deploy manifests.
@@ -455,12 +735,35 @@ type Artifact struct {
 	// For example: `{"*.py": ".", "css/**/*.css": "app/css"}`.
 	Sync map[string]string `yaml:"sync,omitempty"`
 
+	// Requires (alpha) lists the artifacts that must be built before this one.
+	// A change to a required artifact triggers a rebuild of this artifact.
+	Requires []*ArtifactDependency `yaml:"requires,omitempty"`
+
+	// Platforms (alpha) lists the platforms, formatted as `os/arch[/variant]`,
+	// to build the image for. The images are pushed and assembled into a manifest list.
+	// Only supported by Docker artifacts built locally with BuildKit or with a Kaniko
+	// `image` that supports `--customPlatform`.
+	// For example: `["linux/amd64", "linux/arm64"]`.
+	Platforms []string `yaml:"platforms,omitempty"`
+
 	ArtifactType `yaml:",inline"`
 
 	// BuilderPlugin is the plugin used to build this artifact.
 	BuilderPlugin *BuilderPlugin `yaml:"plugin,omitempty"`
 }
 
+// ArtifactDependency describes a dependency on another artifact built by Skaffold.
+type ArtifactDependency struct {
+	// ImageName is the name of the required artifact.
+	// For example: `gcr.io/k8s-skaffold/base`.
+	ImageName string `yaml:"image" yamltags:"required"`
+
+	// Alias is the name of the build arg set to the fully qualified name of the
+	// freshly built required image. Only used by Docker artifacts.
+	// For example: `BASE`, used in the Dockerfile with `ARG BASE` and `FROM $BASE`.
+	Alias string `yaml:"alias,omitempty"`
+}
+
 // Profile (beta) profiles are used to override any `build`, `test` or `deploy` configuration.
 type Profile struct {
 	// Name is a unique profile name.
@@ -533,6 +836,17 @@ type ArtifactType struct {
 	// JibGradleArtifact (alpha) builds images using the
 	// [Jib plugin for Gradle](https://github.com/GoogleContainerTools/jib/tree/master/jib-gradle-plugin).
 	JibGradleArtifact *JibGradleArtifact `yaml:"jibGradle,omitempty" yamltags:"oneOf=artifact"`
+
+	// CustomArtifact (alpha) builds images with a user supplied command.
+	CustomArtifact *CustomArtifact `yaml:"custom,omitempty" yamltags:"oneOf=artifact"`
+
+	// BuildpackArtifact (alpha) builds images from source using
+	// [Cloud Native Buildpacks](https://buildpacks.io/), without a Dockerfile.
+	BuildpackArtifact *BuildpackArtifact `yaml:"buildpacks,omitempty" yamltags:"oneOf=artifact"`
+
+	// GoImageArtifact (alpha) builds images from Go `main` packages
+	// without a Docker daemon.
+	GoImageArtifact *GoImageArtifact `yaml:"goImage,omitempty" yamltags:"oneOf=artifact"`
 }
 
 // DockerArtifact (beta) describes an artifact built from a Dockerfile,
@@ -552,6 +866,28 @@ type DockerArtifact struct {
 	// CacheFrom lists the Docker images to consider as cache sources.
 	// For example: `["golang:1.10.1-alpine3.7", "alpine:3.7"]`.
 	CacheFrom []string `yaml:"cacheFrom,omitempty"`
+
+	// Secrets (alpha) are the build secrets exposed to `RUN --mount=type=secret` instructions.
+	// Requires a local build with `useBuildkit: true`. They are only passed to the `docker` CLI.
+	Secrets []*DockerSecret `yaml:"secrets,omitempty"`
+
+	// SSH (alpha) lists the SSH agent sockets or keys exposed to `RUN --mount=type=ssh`
+	// instructions, in the `default|<id>[=<socket>|<key>[,<key>]]` format.
+	// Requires a local build with `useBuildkit: true`. They are only passed to the `docker` CLI.
+	// For example: `["default"]`.
+	SSH []string `yaml:"ssh,omitempty"`
+}
+
+// DockerSecret (alpha) is a build secret read from a file or an environment variable.
+type DockerSecret struct {
+	// ID is the id of the secret, as used by `RUN --mount=type=secret,id=<id>`.
+	ID string `yaml:"id" yamltags:"required"`
+
+	// Src is the path to the file holding the secret, relative to the artifact's context.
+	Src string `yaml:"src,omitempty"`
+
+	// Env is the name of the environment variable holding the secret.
+	Env string `yaml:"env,omitempty"`
 }
 
 // BazelArtifact (beta) describes an artifact built with [Bazel](https://bazel.build/).
@@ -589,3 +925,93 @@ type JibGradleArtifact struct {
 	// For example: `["--no-build-cache"]`.
 	Flags []string `yaml:"args,omitempty"`
 }
+
+// CustomArtifact (alpha) describes an artifact built with a user supplied command,
+// such as a shell script, `make` or `buildah`.
+type CustomArtifact struct {
+	// BuildCommand is the command executed to build the image.
+	// It is run in the artifact's workspace, with those environment variables injected:
+	//   IMAGE          |  Fully qualified name of the image to build, including its tag.
+	//   PUSH_IMAGE     |  `true` if the command is expected to push the image to a registry.
+	//   BUILD_CONTEXT  |  Absolute path to the artifact's workspace.
+	// For example: `./build.sh`.
+	BuildCommand string `yaml:"buildCommand,omitempty" yamltags:"required"`
+
+	// Dependencies lists the files that trigger a rebuild of the artifact when modified.
+	// Defaults to all the files in the artifact's workspace.
+	Dependencies *CustomDependencies `yaml:"dependencies,omitempty"`
+}
+
+// CustomDependencies lists the files that a custom artifact depends on.
+// Only one of `paths` or `command` should be set.
+type CustomDependencies struct {
+	// Paths lists the files, directories or glob patterns, relative to the workspace, to watch.
+	// For example: `["src", "Makefile"]`.
+	Paths []string `yaml:"paths,omitempty" yamltags:"oneOf=dependencies"`
+
+	// Ignore lists the files, directories or glob patterns, relative to the workspace, to exclude from `paths`.
+	// For example: `["vendor", "docs/*.md"]`.
+	Ignore []string `yaml:"ignore,omitempty"`
+
+	// Command is run in the workspace and should print one dependency per line,
+	// relative to the workspace.
+	// For example: `git ls-files`.
+	Command string `yaml:"command,omitempty" yamltags:"oneOf=dependencies"`
+}
+
+// BuildpackArtifact (alpha) describes an artifact built from source with
+// [Cloud Native Buildpacks](https://buildpacks.io/), using the `pack` CLI.
+type BuildpackArtifact struct {
+	// Builder is the builder image used.
+	// For example: `heroku/buildpacks`.
+	Builder string `yaml:"builder" yamltags:"required"`
+
+	// RunImage overrides the stack's default run image.
+	RunImage string `yaml:"runImage,omitempty"`
+
+	// Buildpacks is a list of buildpacks to use, instead of those detected by the builder.
+	// For example: `["heroku/nodejs"]`.
+	Buildpacks []string `yaml:"buildpacks,omitempty"`
+
+	// Env are environment variables, in the `key=value` form, passed to the build.
+	// For example: `["NODE_ENV=production"]`.
+	Env []string `yaml:"env,omitempty"`
+
+	// Dependencies lists the files that trigger a rebuild of the artifact when modified.
+	// Defaults to all the files in the artifact's workspace.
+	Dependencies *BuildpackDependencies `yaml:"dependencies,omitempty"`
+}
+
+// GoImageArtifact (alpha) builds images from a Go `main` package without a Docker daemon.
+// The binary is compiled with `go build` and added as a layer on top of a base image.
+type GoImageArtifact struct {
+	// Package is the `main` package to build, either relative to the workspace
+	// or as an import path.
+	// Defaults to `.`.
+	Package string `yaml:"package,omitempty"`
+
+	// BaseImage is the image the binary is added to. `scratch` means an empty image.
+	// Defaults to `gcr.io/distroless/base`.
+	BaseImage string `yaml:"baseImage,omitempty"`
+
+	// Flags are additional flags passed to `go build`.
+	// For example: `["-ldflags", "-s -w"]`.
+	Flags []string `yaml:"flags,omitempty"`
+
+	// Env are environment variables, in the `key=value` form, passed to `go build`.
+	// `GOOS` and `GOARCH` default to the platform of the base image and have to match it.
+	// For `scratch`, they default to `linux` and `amd64`. `CGO_ENABLED=0` is set by default.
+	// For example: `["GOARCH=arm64"]`.
+	Env []string `yaml:"env,omitempty"`
+}
+
+// BuildpackDependencies lists the files that a buildpacks artifact depends on.
+type BuildpackDependencies struct {
+	// Paths lists the files, directories or glob patterns, relative to the workspace, to watch.
+	// For example: `["src", "package.json"]`.
+	Paths []string `yaml:"paths,omitempty"`
+
+	// Ignore lists the files, directories or glob patterns, relative to the workspace, to exclude from `paths`.
+	// For example: `["node_modules", "docs/*.md"]`.
+	Ignore []string `yaml:"ignore,omitempty"`
+}
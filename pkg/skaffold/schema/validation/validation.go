@@ -0,0 +1,385 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Process checks that a SkaffoldPipeline is valid, once profiles
+// and default values have been applied.
+func Process(config *latest.SkaffoldPipeline) error {
+	var errs []error
+	errs = append(errs, validateArtifactDependencies(config.Build.Artifacts)...)
+	errs = append(errs, validateDockerBuildKit(config.Build)...)
+	errs = append(errs, validateLocalEngine(config.Build)...)
+	errs = append(errs, validateConcurrency(config.Build)...)
+	errs = append(errs, validatePlatforms(config.Build)...)
+	if config.Build.KanikoBuild != nil {
+		errs = append(errs, validateKanikoPodTemplate(config.Build.KanikoBuild.PodTemplate)...)
+		errs = append(errs, validateKanikoReusePod(config.Build.KanikoBuild)...)
+		errs = append(errs, validateKanikoBuildContext(config.Build.KanikoBuild)...)
+	}
+	errs = append(errs, validateIntegrationTests(config.Deploy.IntegrationTests)...)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return errors.New(strings.Join(messages, " | "))
+}
+
+// validateArtifactDependencies makes sure that required artifacts are
+// defined and that artifacts don't depend on each other in a cycle.
+func validateArtifactDependencies(artifacts []*latest.Artifact) []error {
+	byName := map[string]*latest.Artifact{}
+	for _, a := range artifacts {
+		byName[a.ImageName] = a
+	}
+
+	var errs []error
+	for _, a := range artifacts {
+		for _, r := range a.Requires {
+			if _, present := byName[r.ImageName]; !present {
+				errs = append(errs, fmt.Errorf("artifact %s requires %s which is not defined", a.ImageName, r.ImageName))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+
+	var visit func(a *latest.Artifact, path []string) error
+	visit = func(a *latest.Artifact, path []string) error {
+		path = append(path, a.ImageName)
+
+		switch state[a.ImageName] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("cycle detected between artifacts: %s", strings.Join(path, " -> "))
+		}
+
+		state[a.ImageName] = visiting
+		for _, r := range a.Requires {
+			if err := visit(byName[r.ImageName], path); err != nil {
+				return err
+			}
+		}
+		state[a.ImageName] = visited
+
+		return nil
+	}
+
+	for _, a := range artifacts {
+		if err := visit(a, nil); err != nil {
+			return []error{err}
+		}
+	}
+
+	return nil
+}
+
+// validateDockerBuildKit makes sure that Docker artifacts only use secrets
+// and ssh when they are built locally with BuildKit.
+func validateDockerBuildKit(build latest.BuildConfig) []error {
+	useBuildKit := build.LocalBuild != nil && build.LocalBuild.UseBuildkit
+
+	var errs []error
+	for _, a := range build.Artifacts {
+		if a.DockerArtifact == nil || (len(a.DockerArtifact.Secrets) == 0 && len(a.DockerArtifact.SSH) == 0) {
+			continue
+		}
+
+		if !useBuildKit {
+			errs = append(errs, fmt.Errorf("artifact %s uses secrets or ssh, which require a local build with `useBuildkit: true`", a.ImageName))
+		}
+
+		for _, secret := range a.DockerArtifact.Secrets {
+			if (secret.Src == "") == (secret.Env == "") {
+				errs = append(errs, fmt.Errorf("secret %s of artifact %s should have either a src or an env", secret.ID, a.ImageName))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateLocalEngine makes sure that the local container engine is known
+// and that Docker specific options and non-Docker artifacts are only used with Docker.
+func validateLocalEngine(build latest.BuildConfig) []error {
+	local := build.LocalBuild
+	if local == nil {
+		return nil
+	}
+
+	switch local.Engine {
+	case "", docker.DockerEngine:
+		return nil
+	case docker.PodmanEngine, docker.BuildahEngine:
+	default:
+		return []error{fmt.Errorf("unknown container engine %s, should be one of docker, podman or buildah", local.Engine)}
+	}
+
+	var errs []error
+	if local.UseDockerCLI {
+		errs = append(errs, fmt.Errorf("`useDockerCLI` can't be used with the %s engine", local.Engine))
+	}
+	if local.UseBuildkit {
+		errs = append(errs, fmt.Errorf("`useBuildkit` can't be used with the %s engine", local.Engine))
+	}
+	// Clusters can't read images from the engine's storage
+	if local.Push != nil && !*local.Push {
+		errs = append(errs, fmt.Errorf("images built with the %s engine have to be pushed, `push: false` can't be used", local.Engine))
+	}
+	for _, a := range build.Artifacts {
+		if a.DockerArtifact == nil {
+			errs = append(errs, fmt.Errorf("artifact %s can't be built with the %s engine: only Docker artifacts are supported", a.ImageName, local.Engine))
+		}
+	}
+
+	return errs
+}
+
+// validateConcurrency makes sure that the number of concurrent builds isn't negative.
+func validateConcurrency(build latest.BuildConfig) []error {
+	var errs []error
+	if build.LocalBuild != nil && build.LocalBuild.Concurrency != nil && *build.LocalBuild.Concurrency < 0 {
+		errs = append(errs, fmt.Errorf("local build concurrency should be positive or 0, got %d", *build.LocalBuild.Concurrency))
+	}
+	if build.KanikoBuild != nil && build.KanikoBuild.Concurrency != nil && *build.KanikoBuild.Concurrency < 0 {
+		errs = append(errs, fmt.Errorf("kaniko build concurrency should be positive or 0, got %d", *build.KanikoBuild.Concurrency))
+	}
+
+	return errs
+}
+
+// validatePlatforms makes sure that multi-platform images are only built
+// for Docker artifacts, either locally with BuildKit or with Kaniko.
+func validatePlatforms(build latest.BuildConfig) []error {
+	var errs []error
+	for _, a := range build.Artifacts {
+		if len(a.Platforms) == 0 {
+			continue
+		}
+
+		for _, platform := range a.Platforms {
+			if _, err := docker.ParsePlatform(platform); err != nil {
+				errs = append(errs, fmt.Errorf("artifact %s: %s", a.ImageName, err))
+			}
+		}
+
+		if err := platformsSupported(build, a); err != nil {
+			errs = append(errs, fmt.Errorf("artifact %s can't be built for multiple platforms: %s", a.ImageName, err))
+		}
+	}
+
+	return errs
+}
+
+func platformsSupported(build latest.BuildConfig, a *latest.Artifact) error {
+	if a.DockerArtifact == nil || a.BuilderPlugin != nil {
+		return errors.New("only Docker artifacts are supported")
+	}
+
+	switch {
+	case build.LocalBuild != nil:
+		local := build.LocalBuild
+		if local.Engine != "" && local.Engine != docker.DockerEngine {
+			return fmt.Errorf("not supported by the %s engine", local.Engine)
+		}
+		if !local.UseBuildkit {
+			return errors.New("local builds require `useBuildkit: true`")
+		}
+		if local.Push != nil && !*local.Push {
+			return errors.New("images have to be pushed")
+		}
+		return nil
+
+	case build.KanikoBuild != nil:
+		// The default executor doesn't support `--customPlatform`
+		if isDefaultKanikoImage(build.KanikoBuild) {
+			return errors.New("kaniko builds require an `image` that supports the `--customPlatform` flag")
+		}
+		return nil
+
+	default:
+		return errors.New("only local and kaniko builds are supported")
+	}
+}
+
+// validateKanikoPodTemplate makes sure that the overrides of the Kaniko pod
+// can be turned into a valid pod spec.
+func validateKanikoPodTemplate(t *latest.KanikoPodTemplate) []error {
+	if t == nil {
+		return nil
+	}
+
+	var errs []error
+
+	if t.Resources != nil {
+		for _, l := range []*latest.ResourceList{t.Resources.Requests, t.Resources.Limits} {
+			if l == nil {
+				continue
+			}
+			for _, quantity := range []string{l.CPU, l.Memory, l.EphemeralStorage} {
+				if _, err := resource.ParseQuantity(quantity); quantity != "" && err != nil {
+					errs = append(errs, fmt.Errorf("invalid kaniko pod resource quantity %q", quantity))
+				}
+			}
+		}
+	}
+
+	for _, toleration := range t.Tolerations {
+		switch toleration.Operator {
+		case "", "Equal":
+		case "Exists":
+			if toleration.Value != "" {
+				errs = append(errs, fmt.Errorf("kaniko pod toleration %s with operator Exists should have no value", toleration.Key))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("invalid kaniko pod toleration operator %q, should be Exists or Equal", toleration.Operator))
+		}
+
+		switch toleration.Effect {
+		case "", "NoSchedule", "PreferNoSchedule", "NoExecute":
+		default:
+			errs = append(errs, fmt.Errorf("invalid kaniko pod toleration effect %q", toleration.Effect))
+		}
+	}
+
+	for _, env := range t.Env {
+		if !strings.Contains(env, "=") {
+			errs = append(errs, fmt.Errorf("invalid kaniko pod env %q, should be key=value", env))
+		}
+	}
+
+	volumes := map[string]bool{
+		constants.DefaultKanikoSecretName:             true,
+		constants.DefaultKanikoDockerConfigSecretName: true,
+		constants.DefaultKanikoEmptyDirName:           true,
+		constants.DefaultKanikoCacheDirName:           true,
+	}
+	for _, volume := range t.Volumes {
+		if volumes[volume.Name] {
+			errs = append(errs, fmt.Errorf("kaniko pod volume %s is already defined", volume.Name))
+		}
+		volumes[volume.Name] = true
+
+		sources := 0
+		for _, set := range []bool{volume.Secret != "", volume.ConfigMap != "", volume.HostPath != "", volume.PersistentVolumeClaim != "", volume.EmptyDir} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			errs = append(errs, fmt.Errorf("kaniko pod volume %s should have exactly one source", volume.Name))
+		}
+	}
+
+	for _, mount := range t.VolumeMounts {
+		if !volumes[mount.Name] {
+			errs = append(errs, fmt.Errorf("kaniko pod volume mount %s references an unknown volume", mount.Name))
+		}
+	}
+
+	return errs
+}
+
+// isDefaultKanikoImage returns true when no specific Kaniko executor is configured.
+// Some features require a more recent executor than the default one.
+func isDefaultKanikoImage(kaniko *latest.KanikoBuild) bool {
+	return kaniko.Image == "" || kaniko.Image == constants.DefaultKanikoImage
+}
+
+// validateKanikoReusePod makes sure that a reused Kaniko pod gets its build
+// contexts from the local directory and runs an executor that has a shell
+// and supports `--cleanup`, which the default executor doesn't.
+func validateKanikoReusePod(kaniko *latest.KanikoBuild) []error {
+	if !kaniko.ReusePod {
+		return nil
+	}
+
+	var errs []error
+	if kaniko.BuildContext == nil || kaniko.BuildContext.LocalDir == nil {
+		errs = append(errs, errors.New("`reusePod` requires a `localDir` build context"))
+	}
+	if isDefaultKanikoImage(kaniko) {
+		errs = append(errs, errors.New("`reusePod` requires an `image` with a shell and Kaniko v0.9.0 or later, such as the debug executor"))
+	}
+
+	return errs
+}
+
+// validateKanikoBuildContext makes sure that build contexts which require
+// a more recent executor than the default one come with a specific `image`.
+func validateKanikoBuildContext(kaniko *latest.KanikoBuild) []error {
+	if kaniko.BuildContext == nil || !isDefaultKanikoImage(kaniko) {
+		return nil
+	}
+
+	var errs []error
+	if kaniko.BuildContext.Stdin != nil {
+		errs = append(errs, errors.New("`stdin` requires an `image` that supports the `tar://stdin` build context"))
+	}
+	if s3 := kaniko.BuildContext.S3Bucket; s3 != nil && (s3.Endpoint != "" || s3.PathStyle) {
+		errs = append(errs, errors.New("`s3Bucket` with an `endpoint` or `pathStyle` requires an `image` that supports the `S3_ENDPOINT` and `S3_FORCE_PATH_STYLE` env variables"))
+	}
+
+	return errs
+}
+
+// validateIntegrationTests makes sure that each integration test is
+// either a manifest or a named image.
+func validateIntegrationTests(tests []*latest.IntegrationTest) []error {
+	var errs []error
+
+	for i, t := range tests {
+		switch {
+		case t.Manifest == "" && t.Image == "":
+			errs = append(errs, fmt.Errorf("integration test #%d should have either a `manifest` or an `image`", i))
+		case t.Image != "" && t.Name == "":
+			errs = append(errs, fmt.Errorf("integration test with image %s should have a `name`", t.Image))
+		case t.Manifest != "" && (len(t.Command) > 0 || len(t.Args) > 0):
+			errs = append(errs, fmt.Errorf("integration test %s: `command` and `args` can only be used with `image`", t.Manifest))
+		}
+
+		if t.TimeoutSeconds < 0 {
+			errs = append(errs, fmt.Errorf("integration test #%d has a negative timeout", i))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,620 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func artifact(imageName string, requires ...string) *latest.Artifact {
+	a := &latest.Artifact{ImageName: imageName}
+	for _, r := range requires {
+		a.Requires = append(a.Requires, &latest.ArtifactDependency{ImageName: r})
+	}
+	return a
+}
+
+func TestValidateArtifactDependencies(t *testing.T) {
+	var tests = []struct {
+		description string
+		artifacts   []*latest.Artifact
+		expected    string
+	}{
+		{
+			description: "no dependencies",
+			artifacts:   []*latest.Artifact{artifact("a"), artifact("b")},
+		},
+		{
+			description: "diamond",
+			artifacts:   []*latest.Artifact{artifact("a", "b", "c"), artifact("b", "d"), artifact("c", "d"), artifact("d")},
+		},
+		{
+			description: "unknown artifact",
+			artifacts:   []*latest.Artifact{artifact("a", "unknown")},
+			expected:    "artifact a requires unknown which is not defined",
+		},
+		{
+			description: "self dependency",
+			artifacts:   []*latest.Artifact{artifact("a", "a")},
+			expected:    "cycle detected between artifacts: a -> a",
+		},
+		{
+			description: "cycle",
+			artifacts:   []*latest.Artifact{artifact("a", "b"), artifact("b", "c"), artifact("c", "a")},
+			expected:    "cycle detected between artifacts: a -> b -> c -> a",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := Process(&latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					Artifacts: test.artifacts,
+				},
+			})
+
+			if test.expected == "" {
+				testutil.CheckError(t, false, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, true, err, test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateDockerBuildKit(t *testing.T) {
+	withSecrets := func(secrets ...*latest.DockerSecret) []*latest.Artifact {
+		return []*latest.Artifact{{
+			ImageName: "image",
+			ArtifactType: latest.ArtifactType{
+				DockerArtifact: &latest.DockerArtifact{Secrets: secrets},
+			},
+		}}
+	}
+
+	var tests = []struct {
+		description string
+		build       latest.BuildConfig
+		expected    string
+	}{
+		{
+			description: "secrets with BuildKit",
+			build: latest.BuildConfig{
+				Artifacts: withSecrets(&latest.DockerSecret{ID: "npmrc", Src: ".npmrc"}),
+				BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{UseBuildkit: true}},
+			},
+		},
+		{
+			description: "secrets without BuildKit",
+			build: latest.BuildConfig{
+				Artifacts: withSecrets(&latest.DockerSecret{ID: "npmrc", Src: ".npmrc"}),
+				BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{}},
+			},
+			expected: "artifact image uses secrets or ssh, which require a local build with `useBuildkit: true`",
+		},
+		{
+			description: "ssh on Kaniko",
+			build: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{
+					ImageName: "image",
+					ArtifactType: latest.ArtifactType{
+						DockerArtifact: &latest.DockerArtifact{SSH: []string{"default"}},
+					},
+				}},
+				BuildType: latest.BuildType{KanikoBuild: &latest.KanikoBuild{}},
+			},
+			expected: "artifact image uses secrets or ssh, which require a local build with `useBuildkit: true`",
+		},
+		{
+			description: "secret with both src and env",
+			build: latest.BuildConfig{
+				Artifacts: withSecrets(&latest.DockerSecret{ID: "token", Src: "token.txt", Env: "TOKEN"}),
+				BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{UseBuildkit: true}},
+			},
+			expected: "secret token of artifact image should have either a src or an env",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := Process(&latest.SkaffoldPipeline{Build: test.build})
+
+			if test.expected == "" {
+				testutil.CheckError(t, false, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, true, err, test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateLocalEngine(t *testing.T) {
+	var tests = []struct {
+		description string
+		local       *latest.LocalBuild
+		artifacts   []*latest.Artifact
+		expected    string
+	}{
+		{
+			description: "default engine",
+			local:       &latest.LocalBuild{UseBuildkit: true},
+		},
+		{
+			description: "podman",
+			local:       &latest.LocalBuild{Engine: "podman"},
+		},
+		{
+			description: "unknown engine",
+			local:       &latest.LocalBuild{Engine: "rkt"},
+			expected:    "unknown container engine rkt, should be one of docker, podman or buildah",
+		},
+		{
+			description: "buildah with docker options",
+			local:       &latest.LocalBuild{Engine: "buildah", UseDockerCLI: true, UseBuildkit: true},
+			expected:    "`useDockerCLI` can't be used with the buildah engine | `useBuildkit` can't be used with the buildah engine",
+		},
+		{
+			description: "podman without push",
+			local:       &latest.LocalBuild{Engine: "podman", Push: util.BoolPtr(false)},
+			expected:    "images built with the podman engine have to be pushed, `push: false` can't be used",
+		},
+		{
+			description: "buildah with push",
+			local:       &latest.LocalBuild{Engine: "buildah", Push: util.BoolPtr(true)},
+		},
+		{
+			description: "podman with docker artifact",
+			local:       &latest.LocalBuild{Engine: "podman"},
+			artifacts: []*latest.Artifact{{
+				ImageName:    "image",
+				ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{}},
+			}},
+		},
+		{
+			description: "podman with jib and bazel artifacts",
+			local:       &latest.LocalBuild{Engine: "podman"},
+			artifacts: []*latest.Artifact{
+				{ImageName: "jib", ArtifactType: latest.ArtifactType{JibMavenArtifact: &latest.JibMavenArtifact{}}},
+				{ImageName: "bazel", ArtifactType: latest.ArtifactType{BazelArtifact: &latest.BazelArtifact{}}},
+			},
+			expected: "artifact jib can't be built with the podman engine: only Docker artifacts are supported | artifact bazel can't be built with the podman engine: only Docker artifacts are supported",
+		},
+		{
+			description: "buildah with buildpacks, goImage and custom artifacts",
+			local:       &latest.LocalBuild{Engine: "buildah"},
+			artifacts: []*latest.Artifact{
+				{ImageName: "buildpacks", ArtifactType: latest.ArtifactType{BuildpackArtifact: &latest.BuildpackArtifact{}}},
+				{ImageName: "go", ArtifactType: latest.ArtifactType{GoImageArtifact: &latest.GoImageArtifact{}}},
+				{ImageName: "custom", ArtifactType: latest.ArtifactType{CustomArtifact: &latest.CustomArtifact{}}},
+			},
+			expected: "artifact buildpacks can't be built with the buildah engine: only Docker artifacts are supported | artifact go can't be built with the buildah engine: only Docker artifacts are supported | artifact custom can't be built with the buildah engine: only Docker artifacts are supported",
+		},
+		{
+			description: "default engine with jib artifact",
+			artifacts: []*latest.Artifact{
+				{ImageName: "jib", ArtifactType: latest.ArtifactType{JibMavenArtifact: &latest.JibMavenArtifact{}}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := Process(&latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					Artifacts: test.artifacts,
+					BuildType: latest.BuildType{LocalBuild: test.local},
+				},
+			})
+
+			if test.expected == "" {
+				testutil.CheckError(t, false, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, true, err, test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateConcurrency(t *testing.T) {
+	var tests = []struct {
+		description string
+		build       latest.BuildConfig
+		expected    string
+	}{
+		{
+			description: "default local concurrency",
+			build: latest.BuildConfig{
+				BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{}},
+			},
+		},
+		{
+			description: "no limit",
+			build: latest.BuildConfig{
+				BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{Concurrency: util.IntPtr(0)}},
+			},
+		},
+		{
+			description: "negative local concurrency",
+			build: latest.BuildConfig{
+				BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{Concurrency: util.IntPtr(-1)}},
+			},
+			expected: "local build concurrency should be positive or 0, got -1",
+		},
+		{
+			description: "negative kaniko concurrency",
+			build: latest.BuildConfig{
+				BuildType: latest.BuildType{KanikoBuild: &latest.KanikoBuild{Concurrency: util.IntPtr(-2)}},
+			},
+			expected: "kaniko build concurrency should be positive or 0, got -2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := Process(&latest.SkaffoldPipeline{Build: test.build})
+
+			if test.expected == "" {
+				testutil.CheckError(t, false, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, true, err, test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidatePlatforms(t *testing.T) {
+	withPlatforms := func(platforms ...string) []*latest.Artifact {
+		return []*latest.Artifact{{
+			ImageName: "image",
+			Platforms: platforms,
+			ArtifactType: latest.ArtifactType{
+				DockerArtifact: &latest.DockerArtifact{},
+			},
+		}}
+	}
+
+	var tests = []struct {
+		description string
+		build       latest.BuildConfig
+		expected    string
+	}{
+		{
+			description: "local with BuildKit",
+			build: latest.BuildConfig{
+				Artifacts: withPlatforms("linux/amd64", "linux/arm/v7"),
+				BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{UseBuildkit: true}},
+			},
+		},
+		{
+			description: "kaniko",
+			build: latest.BuildConfig{
+				Artifacts: withPlatforms("linux/arm64"),
+				BuildType: latest.BuildType{KanikoBuild: &latest.KanikoBuild{Image: "gcr.io/kaniko-project/executor:custom"}},
+			},
+		},
+		{
+			description: "kaniko with the default image",
+			build: latest.BuildConfig{
+				Artifacts: withPlatforms("linux/arm64"),
+				BuildType: latest.BuildType{KanikoBuild: &latest.KanikoBuild{Image: constants.DefaultKanikoImage}},
+			},
+			expected: "artifact image can't be built for multiple platforms: kaniko builds require an `image` that supports the `--customPlatform` flag",
+		},
+		{
+			description: "invalid platform",
+			build: latest.BuildConfig{
+				Artifacts: withPlatforms("arm64"),
+				BuildType: latest.BuildType{KanikoBuild: &latest.KanikoBuild{Image: "gcr.io/kaniko-project/executor:custom"}},
+			},
+			expected: `artifact image: invalid platform "arm64", should be os/arch[/variant]`,
+		},
+		{
+			description: "local without BuildKit",
+			build: latest.BuildConfig{
+				Artifacts: withPlatforms("linux/arm64"),
+				BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{}},
+			},
+			expected: "artifact image can't be built for multiple platforms: local builds require `useBuildkit: true`",
+		},
+		{
+			description: "local without push",
+			build: latest.BuildConfig{
+				Artifacts: withPlatforms("linux/arm64"),
+				BuildType: latest.BuildType{LocalBuild: &latest.LocalBuild{UseBuildkit: true, Push: util.BoolPtr(false)}},
+			},
+			expected: "artifact image can't be built for multiple platforms: images have to be pushed",
+		},
+		{
+			description: "google cloud build",
+			build: latest.BuildConfig{
+				Artifacts: withPlatforms("linux/arm64"),
+				BuildType: latest.BuildType{GoogleCloudBuild: &latest.GoogleCloudBuild{}},
+			},
+			expected: "artifact image can't be built for multiple platforms: only local and kaniko builds are supported",
+		},
+		{
+			description: "jib artifact",
+			build: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{
+					ImageName: "image",
+					Platforms: []string{"linux/arm64"},
+					ArtifactType: latest.ArtifactType{
+						JibMavenArtifact: &latest.JibMavenArtifact{},
+					},
+				}},
+				BuildType: latest.BuildType{KanikoBuild: &latest.KanikoBuild{}},
+			},
+			expected: "artifact image can't be built for multiple platforms: only Docker artifacts are supported",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := Process(&latest.SkaffoldPipeline{Build: test.build})
+
+			if test.expected == "" {
+				testutil.CheckError(t, false, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, true, err, test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateKanikoPodTemplate(t *testing.T) {
+	var tests = []struct {
+		description string
+		template    *latest.KanikoPodTemplate
+		expected    string
+	}{
+		{
+			description: "valid",
+			template: &latest.KanikoPodTemplate{
+				Resources: &latest.ResourceRequirements{
+					Requests: &latest.ResourceList{CPU: "500m", Memory: "1Gi"},
+				},
+				Tolerations: []*latest.Toleration{{Key: "builds", Operator: "Exists", Effect: "NoSchedule"}},
+				Env:         []string{"KEY=VALUE"},
+				Volumes:     []*latest.KanikoVolume{{Name: "cache", EmptyDir: true}},
+				VolumeMounts: []*latest.KanikoVolumeMount{
+					{Name: "cache", MountPath: "/cache"},
+					{Name: "kaniko-secret", MountPath: "/other"},
+				},
+			},
+		},
+		{
+			description: "invalid quantity",
+			template: &latest.KanikoPodTemplate{
+				Resources: &latest.ResourceRequirements{
+					Limits: &latest.ResourceList{Memory: "lots"},
+				},
+			},
+			expected: `invalid kaniko pod resource quantity "lots"`,
+		},
+		{
+			description: "invalid toleration",
+			template: &latest.KanikoPodTemplate{
+				Tolerations: []*latest.Toleration{{Key: "builds", Operator: "Exists", Value: "true", Effect: "Never"}},
+			},
+			expected: `kaniko pod toleration builds with operator Exists should have no value | invalid kaniko pod toleration effect "Never"`,
+		},
+		{
+			description: "invalid env",
+			template:    &latest.KanikoPodTemplate{Env: []string{"KEY"}},
+			expected:    `invalid kaniko pod env "KEY", should be key=value`,
+		},
+		{
+			description: "invalid volumes",
+			template: &latest.KanikoPodTemplate{
+				Volumes: []*latest.KanikoVolume{
+					{Name: "docker-cfg", Secret: "secret"},
+					{Name: "both", Secret: "secret", ConfigMap: "config"},
+				},
+				VolumeMounts: []*latest.KanikoVolumeMount{{Name: "unknown", MountPath: "/unknown"}},
+			},
+			expected: "kaniko pod volume docker-cfg is already defined | kaniko pod volume both should have exactly one source | kaniko pod volume mount unknown references an unknown volume",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := Process(&latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					BuildType: latest.BuildType{KanikoBuild: &latest.KanikoBuild{PodTemplate: test.template}},
+				},
+			})
+
+			if test.expected == "" {
+				testutil.CheckError(t, false, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, true, err, test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateKanikoReusePod(t *testing.T) {
+	var tests = []struct {
+		description string
+		kaniko      *latest.KanikoBuild
+		expected    string
+	}{
+		{
+			description: "local dir and debug image",
+			kaniko: &latest.KanikoBuild{
+				ReusePod:     true,
+				Image:        "gcr.io/kaniko-project/executor:debug-v0.9.0",
+				BuildContext: &latest.KanikoBuildContext{LocalDir: &latest.LocalDir{}},
+			},
+		},
+		{
+			description: "gcs bucket",
+			kaniko: &latest.KanikoBuild{
+				ReusePod:     true,
+				Image:        "gcr.io/kaniko-project/executor:debug-v0.9.0",
+				BuildContext: &latest.KanikoBuildContext{GCSBucket: "bucket"},
+			},
+			expected: "`reusePod` requires a `localDir` build context",
+		},
+		{
+			description: "default image",
+			kaniko: &latest.KanikoBuild{
+				ReusePod:     true,
+				Image:        constants.DefaultKanikoImage,
+				BuildContext: &latest.KanikoBuildContext{LocalDir: &latest.LocalDir{}},
+			},
+			expected: "`reusePod` requires an `image` with a shell and Kaniko v0.9.0 or later, such as the debug executor",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := Process(&latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					BuildType: latest.BuildType{KanikoBuild: test.kaniko},
+				},
+			})
+
+			if test.expected == "" {
+				testutil.CheckError(t, false, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, true, err, test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateKanikoBuildContext(t *testing.T) {
+	var tests = []struct {
+		description string
+		kaniko      *latest.KanikoBuild
+		expected    string
+	}{
+		{
+			description: "stdin with the default image",
+			kaniko: &latest.KanikoBuild{
+				Image:        constants.DefaultKanikoImage,
+				BuildContext: &latest.KanikoBuildContext{Stdin: &latest.StdinContext{}},
+			},
+			expected: "`stdin` requires an `image` that supports the `tar://stdin` build context",
+		},
+		{
+			description: "stdin with a specific image",
+			kaniko: &latest.KanikoBuild{
+				Image:        "gcr.io/kaniko-project/executor:v1.9.0",
+				BuildContext: &latest.KanikoBuildContext{Stdin: &latest.StdinContext{}},
+			},
+		},
+		{
+			description: "aws s3 with the default image",
+			kaniko: &latest.KanikoBuild{
+				Image:        constants.DefaultKanikoImage,
+				BuildContext: &latest.KanikoBuildContext{S3Bucket: &latest.S3Bucket{Bucket: "bucket"}},
+			},
+		},
+		{
+			description: "minio with the default image",
+			kaniko: &latest.KanikoBuild{
+				Image: constants.DefaultKanikoImage,
+				BuildContext: &latest.KanikoBuildContext{S3Bucket: &latest.S3Bucket{
+					Bucket:    "bucket",
+					Endpoint:  "http://minio:9000",
+					PathStyle: true,
+				}},
+			},
+			expected: "`s3Bucket` with an `endpoint` or `pathStyle` requires an `image` that supports the `S3_ENDPOINT` and `S3_FORCE_PATH_STYLE` env variables",
+		},
+		{
+			description: "minio with a specific image",
+			kaniko: &latest.KanikoBuild{
+				Image: "gcr.io/kaniko-project/executor:v1.9.0",
+				BuildContext: &latest.KanikoBuildContext{S3Bucket: &latest.S3Bucket{
+					Bucket:   "bucket",
+					Endpoint: "http://minio:9000",
+				}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := Process(&latest.SkaffoldPipeline{
+				Build: latest.BuildConfig{
+					BuildType: latest.BuildType{KanikoBuild: test.kaniko},
+				},
+			})
+
+			if test.expected == "" {
+				testutil.CheckError(t, false, err)
+			} else {
+				testutil.CheckErrorAndDeepEqual(t, true, err, test.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateIntegrationTests(t *testing.T) {
+	var tests = []struct {
+		description string
+		tests       []*latest.IntegrationTest
+		shouldErr   bool
+	}{
+		{
+			description: "manifest",
+			tests:       []*latest.IntegrationTest{{Manifest: "job.yaml"}},
+		},
+		{
+			description: "image",
+			tests:       []*latest.IntegrationTest{{Name: "smoke", Image: "busybox", Command: []string{"true"}}},
+		},
+		{
+			description: "neither manifest nor image",
+			tests:       []*latest.IntegrationTest{{Name: "smoke"}},
+			shouldErr:   true,
+		},
+		{
+			description: "image without name",
+			tests:       []*latest.IntegrationTest{{Image: "busybox"}},
+			shouldErr:   true,
+		},
+		{
+			description: "manifest with command",
+			tests:       []*latest.IntegrationTest{{Manifest: "job.yaml", Command: []string{"true"}}},
+			shouldErr:   true,
+		},
+		{
+			description: "negative timeout",
+			tests:       []*latest.IntegrationTest{{Manifest: "job.yaml", TimeoutSeconds: -1}},
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := Process(&latest.SkaffoldPipeline{
+				Deploy: latest.DeployConfig{IntegrationTests: test.tests},
+			})
+
+			testutil.CheckError(t, test.shouldErr, err)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Credentials are the credentials used to sign requests to S3.
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3CredentialsFromEnv reads the credentials from the standard AWS env variables.
+func S3CredentialsFromEnv() (*S3Credentials, error) {
+	creds := &S3Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY should be set")
+	}
+
+	return creds, nil
+}
+
+// UploadToS3 uploads the artifact's sources to an S3-compatible bucket.
+// The object is named after the checksum of the sources, so that identical
+// sources are uploaded only once. It returns the object's name.
+func UploadToS3(ctx context.Context, a *latest.Artifact, bucket *latest.S3Bucket, creds *S3Credentials, dependencies []string) (string, error) {
+	f, err := ioutil.TempFile("", "context")
+	if err != nil {
+		return "", errors.Wrap(err, "creating temporary context tarball")
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	hash := sha256.New()
+	if err := TarGz(ctx, io.MultiWriter(f, hash), a, dependencies); err != nil {
+		return "", err
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	objectName := fmt.Sprintf("context-%s.tar.gz", checksum)
+
+	objectURL, err := S3ObjectURL(bucket, objectName)
+	if err != nil {
+		return "", err
+	}
+
+	exists, err := s3ObjectExists(ctx, objectURL, bucket.Region, creds)
+	if err != nil {
+		return "", errors.Wrap(err, "checking for existing sources")
+	}
+	if exists {
+		logrus.Debugf("Sources already uploaded to %s", objectURL)
+		return objectName, nil
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", errors.Wrap(err, "reading context tarball")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", errors.Wrap(err, "reading context tarball")
+	}
+
+	req, err := http.NewRequest(http.MethodPut, objectURL, f)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := doS3Request(ctx, req, checksum, bucket.Region, creds)
+	if err != nil {
+		return "", errors.Wrap(err, "uploading sources")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return "", fmt.Errorf("uploading sources: %s: %s", resp.Status, body)
+	}
+
+	return objectName, nil
+}
+
+// S3ObjectURL returns the url of an object in a bucket.
+func S3ObjectURL(bucket *latest.S3Bucket, objectName string) (string, error) {
+	endpoint := bucket.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", bucket.Region)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing endpoint %s", endpoint)
+	}
+
+	if bucket.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket.Bucket + "/" + objectName
+	} else {
+		u.Host = bucket.Bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + objectName
+	}
+
+	return u.String(), nil
+}
+
+func s3ObjectExists(ctx context.Context, objectURL, region string, creds *S3Credentials) (bool, error) {
+	req, err := http.NewRequest(http.MethodHead, objectURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := doS3Request(ctx, req, emptyPayloadHash, region, creds)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+}
+
+func doS3Request(ctx context.Context, req *http.Request, payloadHash, region string, creds *S3Credentials) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	signS3Request(req, payloadHash, region, creds, time.Now())
+
+	return http.DefaultClient.Do(req.WithContext(ctx))
+}
+
+// signS3Request signs a request with AWS Signature Version 4.
+// All the headers of the request are signed.
+func signS3Request(req *http.Request, payloadHash, region string, creds *S3Credentials, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	for _, part := range []string{region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	defaultCacheDir  = ".skaffold"
+	defaultCacheFile = "test-cache"
+)
+
+// For testing
+var localDaemon = docker.NewAPIClient
+
+// testCache records the test cases that passed on a given image.
+// Keys are a hash of the image's digest, the test case's config and its files.
+type testCache struct {
+	cacheFile string
+	passed    map[string]bool
+}
+
+// newTestCache reads the test cache from a file.
+// If cacheFile is empty, the cache is stored in `~/.skaffold/test-cache`.
+func newTestCache(cacheFile string) (*testCache, error) {
+	if cacheFile == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return nil, errors.Wrap(err, "retrieving home directory")
+		}
+		cacheFile = filepath.Join(home, defaultCacheDir, defaultCacheFile)
+	}
+
+	if err := util.VerifyOrCreateFile(cacheFile); err != nil {
+		return nil, errors.Wrap(err, "resolving test cache file location")
+	}
+
+	contents, err := ioutil.ReadFile(cacheFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading test cache file")
+	}
+
+	passed := map[string]bool{}
+	if err := yaml.Unmarshal(contents, &passed); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling test cache file")
+	}
+
+	return &testCache{
+		cacheFile: cacheFile,
+		passed:    passed,
+	}, nil
+}
+
+func (c *testCache) hasPassed(key string) bool {
+	return c.passed[key]
+}
+
+// retainPass records that a test case passed and saves the cache.
+func (c *testCache) retainPass(key string) error {
+	c.passed[key] = true
+
+	contents, err := yaml.Marshal(c.passed)
+	if err != nil {
+		return errors.Wrap(err, "marshalling test cache")
+	}
+
+	return ioutil.WriteFile(c.cacheFile, contents, 0644)
+}
+
+// cacheKey computes the key of a test case run on a given image.
+func cacheKey(ctx context.Context, image string, testCase *latest.TestCase, files []string) (string, error) {
+	digest, err := imageDigest(ctx, image)
+	if err != nil {
+		return "", errors.Wrapf(err, "getting digest of %s", image)
+	}
+
+	config, err := json.Marshal(testCase)
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling test config")
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", digest, config)
+
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+	for _, file := range sorted {
+		if err := hashFile(h, file); err != nil {
+			return "", errors.Wrapf(err, "hashing %s", file)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(h io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(h, "%s\n", path)
+	_, err = io.Copy(h, f)
+	return err
+}
+
+// imageDigest gives the digest of a pushed image, or the ID of a local image.
+func imageDigest(ctx context.Context, image string) (string, error) {
+	if i := strings.Index(image, "@"); i != -1 {
+		return image[i+1:], nil
+	}
+
+	localDocker, err := localDaemon()
+	if err != nil {
+		return "", err
+	}
+
+	imageID, err := localDocker.ImageID(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	if imageID == "" {
+		return "", errors.New("image not found")
+	}
+
+	return imageID, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestCachedTests(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("test.sh", "go test ./...")
+
+	testCases := []*latest.TestCase{{
+		ImageName:   "img",
+		CustomTests: []*latest.CustomTest{{Command: "./test.sh", Dependencies: []string{"test.sh"}}},
+	}}
+
+	// A new tester is created for each step, to check that the cache is persisted.
+	testWith := func(command util.Command, tag string) (string, error) {
+		defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+		util.DefaultExecCommand = command
+
+		cache, err := newTestCache(tmpDir.Path("test-cache"))
+		testutil.CheckError(t, false, err)
+
+		tester := FullTester{
+			testCases:  testCases,
+			workingDir: tmpDir.Root(),
+			cache:      cache,
+		}
+
+		var out bytes.Buffer
+		err = tester.Test(context.Background(), &out, []build.Artifact{{ImageName: "img", Tag: tag}})
+		return out.String(), err
+	}
+
+	// Tests that fail are not cached
+	_, err := testWith(testutil.NewFakeCmd(t).WithRunErr("sh -c ./test.sh", errors.New("exit status 1")), "img:1@sha256:one")
+	testutil.CheckError(t, true, err)
+
+	_, err = testWith(testutil.NewFakeCmd(t).WithRun("sh -c ./test.sh"), "img:1@sha256:one")
+	testutil.CheckError(t, false, err)
+
+	// Same digest, same test files
+	out, err := testWith(testutil.NewFakeCmd(t), "img:2@sha256:one")
+	testutil.CheckErrorAndDeepEqual(t, false, err, true, strings.Contains(out, "Testing [img:2@sha256:one]: cached: passed"))
+
+	// Different digest
+	_, err = testWith(testutil.NewFakeCmd(t).WithRun("sh -c ./test.sh"), "img:3@sha256:two")
+	testutil.CheckError(t, false, err)
+
+	// Modified test files
+	tmpDir.Write("test.sh", "go test -v ./...")
+	_, err = testWith(testutil.NewFakeCmd(t).WithRun("sh -c ./test.sh"), "img:3@sha256:two")
+	testutil.CheckError(t, false, err)
+}
+
+func TestImageDigest(t *testing.T) {
+	defer func(l func() (docker.LocalDaemon, error)) { localDaemon = l }(localDaemon)
+	localDaemon = func() (docker.LocalDaemon, error) {
+		return docker.NewLocalDaemon(&testutil.FakeAPIClient{
+			TagToImageID: map[string]string{"img:local": "sha256:local"},
+		}, nil), nil
+	}
+
+	tests := []struct {
+		description string
+		image       string
+		shouldErr   bool
+		expected    string
+	}{
+		{
+			description: "pushed image",
+			image:       "img:tag@sha256:remote",
+			expected:    "sha256:remote",
+		},
+		{
+			description: "local image",
+			image:       "img:local",
+			expected:    "sha256:local",
+		},
+		{
+			description: "unknown image",
+			image:       "img:unknown",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			digest, err := imageDigest(context.Background(), test.image)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, digest)
+		})
+	}
+}
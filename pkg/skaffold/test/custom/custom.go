@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/custom"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Test runs the custom test command, with the image in the environment.
+func (tr *Runner) Test(ctx context.Context, out io.Writer, image string) error {
+	if tr.test.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(tr.test.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	cmd := custom.Command(ctx, tr.dir(), tr.test.Command)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", custom.ImageEnv, image))
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	logrus.Infof("Running custom test for %s: %s", image, tr.test.Command)
+	if err := util.RunCmd(cmd); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("custom test %q timed out after %ds", tr.test.Command, tr.test.TimeoutSeconds)
+		}
+		return errors.Wrapf(err, "running custom test %q", tr.test.Command)
+	}
+
+	return nil
+}
+
+// Dependencies returns the files that trigger a new run of the test.
+func (tr *Runner) Dependencies() ([]string, error) {
+	if len(tr.test.Dependencies) == 0 {
+		return nil, nil
+	}
+
+	files, err := util.ListFiles(tr.workingDir, tr.test.Dependencies, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []string
+	for _, file := range files {
+		deps = append(deps, filepath.Join(tr.workingDir, file))
+	}
+	return deps, nil
+}
+
+func (tr *Runner) dir() string {
+	if filepath.IsAbs(tr.test.Dir) {
+		return tr.test.Dir
+	}
+	return filepath.Join(tr.workingDir, tr.test.Dir)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type recordingCmd struct {
+	cmd *exec.Cmd
+}
+
+func (r *recordingCmd) RunCmdOut(cmd *exec.Cmd) ([]byte, error) {
+	r.cmd = cmd
+	return nil, nil
+}
+
+func (r *recordingCmd) RunCmd(cmd *exec.Cmd) error {
+	r.cmd = cmd
+	return nil
+}
+
+func TestCustomTest(t *testing.T) {
+	var tests = []struct {
+		description string
+		command     *testutil.FakeCmd
+		shouldErr   bool
+	}{
+		{
+			description: "success",
+			command:     testutil.NewFakeCmd(t).WithRun("sh -c docker run $IMAGE go test ./..."),
+		},
+		{
+			description: "failure",
+			command:     testutil.NewFakeCmd(t).WithRunErr("sh -c docker run $IMAGE go test ./...", errors.New("exit status 1")),
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			util.DefaultExecCommand = test.command
+
+			runner := NewRunner(&latest.CustomTest{Command: "docker run $IMAGE go test ./..."}, ".")
+			err := runner.Test(context.Background(), ioutil.Discard, "gcr.io/project/image:tag")
+
+			testutil.CheckError(t, test.shouldErr, err)
+		})
+	}
+}
+
+func TestCustomTestEnvironment(t *testing.T) {
+	var tests = []struct {
+		description string
+		dir         string
+		expectedDir string
+	}{
+		{
+			description: "defaults to the working directory",
+			expectedDir: "/project",
+		},
+		{
+			description: "relative directory",
+			dir:         "tests",
+			expectedDir: filepath.Join("/project", "tests"),
+		},
+		{
+			description: "absolute directory",
+			dir:         "/tests",
+			expectedDir: "/tests",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			recorder := &recordingCmd{}
+			util.DefaultExecCommand = recorder
+
+			runner := NewRunner(&latest.CustomTest{Command: "./test.sh", Dir: test.dir}, "/project")
+			err := runner.Test(context.Background(), ioutil.Discard, "gcr.io/project/image:tag")
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expectedDir, recorder.cmd.Dir)
+			testutil.CheckDeepEqual(t, "IMAGE=gcr.io/project/image:tag", recorder.cmd.Env[len(recorder.cmd.Env)-1])
+		})
+	}
+}
+
+func TestCustomTestDependencies(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("test/main_test.go", "").
+		Write("test/testdata/input.json", "").
+		Write("go.mod", "").
+		Write("main.go", "")
+
+	runner := NewRunner(&latest.CustomTest{
+		Command:      "go test ./test/...",
+		Dependencies: []string{"test", "*.mod"},
+	}, tmpDir.Root())
+	deps, err := runner.Dependencies()
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{
+		tmpDir.Path("go.mod"),
+		tmpDir.Path("test/main_test.go"),
+		tmpDir.Path("test/testdata/input.json"),
+	}, deps)
+}
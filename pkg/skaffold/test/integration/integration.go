@@ -0,0 +1,238 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	// jobNameLabel is set by Kubernetes on the pods of a Job.
+	jobNameLabel = "job-name"
+
+	// flushTimeout is how long to wait for the logs of a Job
+	// once it has completed.
+	flushTimeout = 5 * time.Second
+)
+
+var (
+	// For testing
+	randomID = util.RandomFourCharacterID
+)
+
+// Run runs the integration tests one after the other, against the given builds.
+// It stops at the first failing test. Jobs are deleted once they have completed.
+func (r *Runner) Run(ctx context.Context, out io.Writer, builds []build.Artifact) error {
+	if len(r.tests) == 0 {
+		return nil
+	}
+
+	client, err := kubernetes.Client()
+	if err != nil {
+		return errors.Wrap(err, "getting k8s client")
+	}
+
+	for _, test := range r.tests {
+		job, err := r.job(test, builds)
+		if err != nil {
+			return err
+		}
+
+		if err := r.runJob(ctx, out, client, job, builds, test); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) runJob(ctx context.Context, out io.Writer, client kube.Interface, job *batchv1.Job, builds []build.Artifact, test *latest.IntegrationTest) error {
+	jobs := client.BatchV1().Jobs(job.Namespace)
+
+	color.Default.Fprintf(out, "Running integration test %s\n", job.Name)
+	if _, err := jobs.Create(job); err != nil {
+		return errors.Wrapf(err, "creating job %s", job.Name)
+	}
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		if err := jobs.Delete(job.Name, &metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			logrus.Warnf("Unable to delete job %s: %s", job.Name, err)
+		}
+	}()
+
+	logger := kubernetes.NewLogAggregator(out, imageNames(builds), jobPods(job.Name), []string{job.Namespace})
+	if err := logger.Start(ctx); err != nil {
+		return errors.Wrap(err, "streaming job logs")
+	}
+	defer logger.Stop()
+
+	timeout := time.Duration(test.TimeoutSeconds) * time.Second
+	if err := kubernetes.WaitForJobToStabilize(ctx, client, job.Namespace, job.Name, timeout); err != nil {
+		return errors.Wrapf(err, "waiting for job %s to start", job.Name)
+	}
+
+	err := kubernetes.WaitForJobToComplete(ctx, client, job.Namespace, job.Name, timeout)
+	logger.Flush(flushTimeout)
+	if err != nil {
+		if exitErr := exitStatus(client, job); exitErr != nil {
+			return exitErr
+		}
+		return errors.Wrapf(err, "waiting for job %s to complete", job.Name)
+	}
+
+	return nil
+}
+
+// job creates the Job for a test, either from its manifest or from its image.
+func (r *Runner) job(test *latest.IntegrationTest, builds []build.Artifact) (*batchv1.Job, error) {
+	var job *batchv1.Job
+
+	if test.Manifest != "" {
+		var err error
+		if job, err = r.readJob(test.Manifest); err != nil {
+			return nil, err
+		}
+	} else {
+		backoffLimit := int32(0)
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: test.Name,
+			},
+			Spec: batchv1.JobSpec{
+				BackoffLimit: &backoffLimit,
+				Template: v1.PodTemplateSpec{
+					Spec: v1.PodSpec{
+						RestartPolicy: v1.RestartPolicyNever,
+						Containers: []v1.Container{{
+							Name:    "test",
+							Image:   test.Image,
+							Command: test.Command,
+							Args:    test.Args,
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	name := job.Name
+	if test.Name != "" {
+		name = test.Name
+	}
+	job.Name = fmt.Sprintf("%s-%s", name, randomID())
+	job.GenerateName = ""
+	if job.Namespace == "" {
+		job.Namespace = r.namespace
+	}
+
+	for i, c := range job.Spec.Template.Spec.Containers {
+		job.Spec.Template.Spec.Containers[i].Image = resolveImage(c.Image, builds)
+	}
+
+	return job, nil
+}
+
+func (r *Runner) readJob(manifest string) (*batchv1.Job, error) {
+	path := manifest
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.workingDir, path)
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading job manifest %s", manifest)
+	}
+
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(buf, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding job manifest %s", manifest)
+	}
+
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return nil, fmt.Errorf("%s should be a Job manifest", manifest)
+	}
+
+	return job, nil
+}
+
+// exitStatus returns an error describing the first container
+// of the Job's pods that exited with a non zero code.
+func exitStatus(client kube.Interface, job *batchv1.Job) error {
+	pods, err := client.CoreV1().Pods(job.Namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", jobNameLabel, job.Name),
+	})
+	if err != nil {
+		logrus.Debugf("Unable to list the pods of job %s: %s", job.Name, err)
+		return nil
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+				return fmt.Errorf("integration test %s failed: container %s exited with code %d", job.Name, status.Name, terminated.ExitCode)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveImage replaces the name of a built artifact with its tag.
+func resolveImage(image string, builds []build.Artifact) string {
+	for _, b := range builds {
+		if image == b.ImageName || strings.HasPrefix(image, b.ImageName+":") {
+			return b.Tag
+		}
+	}
+
+	return image
+}
+
+func imageNames(builds []build.Artifact) []string {
+	var names []string
+	for _, b := range builds {
+		names = append(names, b.ImageName)
+	}
+	return names
+}
+
+// jobPods selects the pods of a Job.
+type jobPods string
+
+func (j jobPods) Select(pod *v1.Pod) bool {
+	return pod.Labels[jobNameLabel] == string(j)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	pkgkubernetes "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const jobManifest = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: smoke
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: smoke
+        image: gcr.io/project/smoke-tests
+`
+
+func TestJob(t *testing.T) {
+	defer func(f func() string) { randomID = f }(randomID)
+	randomID = func() string { return "abcd" }
+
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+	tmpDir.Write("job.yaml", jobManifest)
+
+	builds := []build.Artifact{{ImageName: "gcr.io/project/smoke-tests", Tag: "gcr.io/project/smoke-tests:v1"}}
+	runner := NewRunner(nil, "", tmpDir.Root())
+
+	// From a manifest
+	job, err := runner.job(&latest.IntegrationTest{Manifest: "job.yaml"}, builds)
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, "smoke-abcd", job.Name)
+	testutil.CheckDeepEqual(t, "default", job.Namespace)
+	testutil.CheckDeepEqual(t, "gcr.io/project/smoke-tests:v1", job.Spec.Template.Spec.Containers[0].Image)
+
+	// From an image
+	job, err = runner.job(&latest.IntegrationTest{Name: "check", Image: "gcr.io/project/smoke-tests", Command: []string{"./check.sh"}}, builds)
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, "check-abcd", job.Name)
+	testutil.CheckDeepEqual(t, v1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	testutil.CheckDeepEqual(t, []v1.Container{{
+		Name:    "test",
+		Image:   "gcr.io/project/smoke-tests:v1",
+		Command: []string{"./check.sh"},
+	}}, job.Spec.Template.Spec.Containers)
+
+	// Not a Job
+	tmpDir.Write("pod.yaml", "apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\n")
+	_, err = runner.job(&latest.IntegrationTest{Manifest: "pod.yaml"}, builds)
+	testutil.CheckError(t, true, err)
+}
+
+func TestRun(t *testing.T) {
+	var tests = []struct {
+		description   string
+		status        batchv1.JobStatus
+		pods          []runtime.Object
+		shouldErr     bool
+		expectedError string
+	}{
+		{
+			description: "success",
+			status:      batchv1.JobStatus{Succeeded: 1},
+		},
+		{
+			description: "failure",
+			status: batchv1.JobStatus{
+				Failed: 1,
+				Conditions: []batchv1.JobCondition{{
+					Type:    batchv1.JobFailed,
+					Status:  v1.ConditionTrue,
+					Message: "Job has reached the specified backoff limit",
+				}},
+			},
+			pods: []runtime.Object{&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "check-abcd-xyz",
+					Namespace: "ns",
+					Labels:    map[string]string{jobNameLabel: "check-abcd"},
+				},
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{{
+						Name:  "test",
+						State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 3}},
+					}},
+				},
+			}},
+			shouldErr:     true,
+			expectedError: "integration test check-abcd failed: container test exited with code 3",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			defer func(f func() string) { randomID = f }(randomID)
+			randomID = func() string { return "abcd" }
+
+			client := fake.NewSimpleClientset(test.pods...)
+			// Jobs complete as soon as they are created.
+			client.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				name := action.(k8stesting.GetAction).GetName()
+				return true, &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: test.status}, nil
+			})
+
+			defer func(c func() (kubernetes.Interface, error)) { pkgkubernetes.Client = c }(pkgkubernetes.Client)
+			pkgkubernetes.Client = func() (kubernetes.Interface, error) { return client, nil }
+
+			runner := NewRunner([]*latest.IntegrationTest{{Name: "check", Image: "busybox", TimeoutSeconds: 5}}, "ns", ".")
+			err := runner.Run(context.Background(), ioutil.Discard, nil)
+
+			testutil.CheckError(t, test.shouldErr, err)
+			if test.shouldErr {
+				testutil.CheckDeepEqual(t, test.expectedError, err.Error())
+			}
+
+			jobs, err := client.BatchV1().Jobs("ns").List(metav1.ListOptions{})
+			testutil.CheckErrorAndDeepEqual(t, false, err, 0, len(jobs.Items))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import "github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+
+// Runner runs the integration tests, as Kubernetes Jobs, after a deployment.
+type Runner struct {
+	tests      []*latest.IntegrationTest
+	namespace  string
+	workingDir string
+}
+
+// NewRunner creates a new integration.Runner. Jobs are created in the given
+// namespace, unless their manifest says otherwise.
+func NewRunner(tests []*latest.IntegrationTest, namespace string, workingDir string) *Runner {
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	return &Runner{
+		tests:      tests,
+		namespace:  namespace,
+		workingDir: workingDir,
+	}
+}
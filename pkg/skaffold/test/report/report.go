@@ -0,0 +1,161 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Result is the outcome of a single test on an image.
+type Result struct {
+	Image    string        `json:"image"`
+	Runner   string        `json:"runner"`
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"-"`
+	Passed   bool          `json:"passed"`
+	Output   string        `json:"output,omitempty"`
+}
+
+// MarshalJSON writes the duration in seconds, like the JUnit report,
+// rather than as a number of nanoseconds.
+func (r *Result) MarshalJSON() ([]byte, error) {
+	type result Result
+	return json.Marshal(&struct {
+		*result
+		DurationSeconds float64 `json:"durationSeconds"`
+	}{
+		result:          (*result)(r),
+		DurationSeconds: math.Round(r.Duration.Seconds()*1000) / 1000,
+	})
+}
+
+// Write writes the results to a file. The format depends on the file's
+// extension: `.json` for JSON and JUnit XML otherwise.
+func Write(path string, results []*Result) error {
+	var (
+		buf []byte
+		err error
+	)
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		buf, err = JSON(results)
+	} else {
+		buf, err = JUnit(results)
+	}
+	if err != nil {
+		return errors.Wrap(err, "formatting test report")
+	}
+
+	if err := ioutil.WriteFile(path, buf, 0644); err != nil {
+		return errors.Wrapf(err, "writing test report %s", path)
+	}
+
+	return nil
+}
+
+// JSON formats the results as a JSON array.
+func JSON(results []*Result) ([]byte, error) {
+	if results == nil {
+		results = []*Result{}
+	}
+
+	return json.MarshalIndent(results, "", "  ")
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Output  string `xml:",chardata"`
+}
+
+// JUnit formats the results as JUnit XML, with one test suite per image.
+func JUnit(results []*Result) ([]byte, error) {
+	var suites junitTestSuites
+	index := map[string]int{}
+	durations := map[string]time.Duration{}
+
+	for _, r := range results {
+		i, found := index[r.Image]
+		if !found {
+			i = len(suites.Suites)
+			index[r.Image] = i
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: r.Image})
+		}
+
+		testCase := junitTestCase{
+			Name:      r.Name,
+			Classname: r.Runner,
+			Time:      seconds(r.Duration),
+		}
+		if r.Passed {
+			testCase.SystemOut = r.Output
+		} else {
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%s test failed", r.Runner),
+				Output:  r.Output,
+			}
+			suites.Suites[i].Failures++
+		}
+
+		suites.Suites[i].Tests++
+		suites.Suites[i].Cases = append(suites.Suites[i].Cases, testCase)
+		durations[r.Image] += r.Duration
+	}
+
+	for i, suite := range suites.Suites {
+		suites.Suites[i].Time = seconds(durations[suite.Name])
+	}
+
+	buf, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), buf...), nil
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
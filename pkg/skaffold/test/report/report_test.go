@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+var results = []*Result{
+	{Image: "img:1", Runner: "structure", Name: "has ls", Duration: 1500 * time.Millisecond, Passed: true, Output: "ok"},
+	{Image: "img:1", Runner: "custom", Name: "./test.sh", Duration: 500 * time.Millisecond, Passed: false, Output: "boom"},
+	{Image: "other:2", Runner: "custom", Name: "./other.sh", Passed: true},
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		description string
+		results     []*Result
+		expected    string
+	}{
+		{
+			description: "no results",
+			expected:    "[]",
+		},
+		{
+			description: "one result",
+			results:     results[2:],
+			expected: `[
+  {
+    "image": "other:2",
+    "runner": "custom",
+    "name": "./other.sh",
+    "passed": true,
+    "durationSeconds": 0
+  }
+]`,
+		},
+		{
+			description: "duration in seconds",
+			results:     results[:1],
+			expected: `[
+  {
+    "image": "img:1",
+    "runner": "structure",
+    "name": "has ls",
+    "passed": true,
+    "output": "ok",
+    "durationSeconds": 1.5
+  }
+]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			buf, err := JSON(test.results)
+
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, string(buf))
+		})
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	buf, err := JUnit(results)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="img:1" tests="2" failures="1" time="2.000">
+    <testcase name="has ls" classname="structure" time="1.500">
+      <system-out>ok</system-out>
+    </testcase>
+    <testcase name="./test.sh" classname="custom" time="0.500">
+      <failure message="custom test failed">boom</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="other:2" tests="1" failures="0" time="0.000">
+    <testcase name="./other.sh" classname="custom" time="0.000"></testcase>
+  </testsuite>
+</testsuites>`
+	testutil.CheckErrorAndDeepEqual(t, false, err, expected, string(buf))
+}
+
+func TestWrite(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tests := []struct {
+		description string
+		file        string
+		expected    string
+	}{
+		{
+			description: "json",
+			file:        "report.json",
+			expected:    "[\n  {",
+		},
+		{
+			description: "junit",
+			file:        "report.xml",
+			expected:    "<?xml",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			path := filepath.Join(tmpDir.Root(), test.file)
+
+			err := Write(path, results)
+			testutil.CheckError(t, false, err)
+
+			buf, err := ioutil.ReadFile(path)
+			testutil.CheckErrorAndDeepEqual(t, false, err, test.expected, string(buf[:len(test.expected)]))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structure
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Sections of a container-structure-test file that skaffold evaluates itself.
+const (
+	fileExistenceTestsKey = "fileExistenceTests"
+	fileContentTestsKey   = "fileContentTests"
+	metadataTestKey       = "metadataTest"
+)
+
+// Sections that are kept in every file passed to container-structure-test.
+var sharedKeys = map[string]bool{
+	"schemaVersion": true,
+	"globalEnvVars": true,
+}
+
+// testFile is a container-structure-test file.
+type testFile struct {
+	FileExistenceTests []*fileExistenceTest `yaml:"fileExistenceTests"`
+	FileContentTests   []*fileContentTest   `yaml:"fileContentTests"`
+	MetadataTest       *metadataTest        `yaml:"metadataTest"`
+
+	// raw is the whole file, including the sections, like `commandTests`,
+	// that need to run a container.
+	raw yaml.MapSlice
+}
+
+type fileExistenceTest struct {
+	Name           string `yaml:"name"`
+	Path           string `yaml:"path"`
+	ShouldExist    bool   `yaml:"shouldExist"`
+	Permissions    string `yaml:"permissions"`
+	UID            *int   `yaml:"uid"`
+	GID            *int   `yaml:"gid"`
+	IsExecutableBy string `yaml:"isExecutableBy"`
+}
+
+type fileContentTest struct {
+	Name             string   `yaml:"name"`
+	Path             string   `yaml:"path"`
+	ExpectedContents []string `yaml:"expectedContents"`
+	ExcludedContents []string `yaml:"excludedContents"`
+}
+
+type metadataTest struct {
+	Env              []*keyValue `yaml:"env"`
+	Labels           []*keyValue `yaml:"labels"`
+	ExposedPorts     []string    `yaml:"exposedPorts"`
+	UnexposedPorts   []string    `yaml:"unexposedPorts"`
+	Volumes          []string    `yaml:"volumes"`
+	UnmountedVolumes []string    `yaml:"unmountedVolumes"`
+	Entrypoint       *[]string   `yaml:"entrypoint"`
+	Cmd              *[]string   `yaml:"cmd"`
+	Workdir          string      `yaml:"workdir"`
+	User             string      `yaml:"user"`
+}
+
+type keyValue struct {
+	Key     string `yaml:"key"`
+	Value   string `yaml:"value"`
+	IsRegex bool   `yaml:"isRegex"`
+}
+
+func readTestFile(path string) (*testFile, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+
+	tf := &testFile{}
+	if err := yaml.Unmarshal(buf, tf); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+	if err := yaml.Unmarshal(buf, &tf.raw); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+
+	return tf, nil
+}
+
+// remaining returns the sections of the file that are not evaluated
+// by skaffold, or nil if there's nothing left for container-structure-test.
+// File tests are kept if the image's file system is not available.
+func (tf *testFile) remaining(withFileTests bool) yaml.MapSlice {
+	var (
+		remaining yaml.MapSlice
+		hasTests  bool
+	)
+
+	for _, item := range tf.raw {
+		key, _ := item.Key.(string)
+		switch {
+		case sharedKeys[key]:
+		case key == metadataTestKey:
+			continue
+		case key == fileExistenceTestsKey || key == fileContentTestsKey:
+			if !withFileTests {
+				continue
+			}
+			hasTests = true
+		default:
+			hasTests = true
+		}
+
+		remaining = append(remaining, item)
+	}
+
+	if !hasTests {
+		return nil
+	}
+	return remaining
+}
+
+func (tf *testFile) hasFileTests() bool {
+	return len(tf.FileExistenceTests) > 0 || len(tf.FileContentTests) > 0
+}
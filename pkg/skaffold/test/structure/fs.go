@@ -0,0 +1,238 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structure
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	opaqueWhiteout = ".wh..wh..opq"
+	maxSymlinks    = 40
+)
+
+// imageFS is the file system of an image, obtained by applying its layers
+// one on top of the other. Only the contents of some files are kept.
+type imageFS struct {
+	files    map[string]*tar.Header
+	contents map[string][]byte
+}
+
+// readImageFS reads the layers of an image, keeping the contents of the given files.
+// Symbolic and hard links to those files are followed.
+func readImageFS(img v1.Image, paths []string) (*imageFS, error) {
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing layers")
+	}
+
+	fs := &imageFS{}
+
+	// The targets of links are only known once every layer is applied.
+	wanted := map[string]bool{}
+	for _, p := range paths {
+		wanted[cleanPath(p)] = true
+	}
+
+	for {
+		if err := fs.apply(layers, wanted); err != nil {
+			return nil, err
+		}
+
+		more := false
+		for p := range wanted {
+			if target := fs.contentPath(p); !wanted[target] && fs.files[target] != nil {
+				wanted[target] = true
+				more = true
+			}
+		}
+		if !more {
+			break
+		}
+	}
+
+	return fs, nil
+}
+
+func (fs *imageFS) apply(layers []v1.Layer, wanted map[string]bool) error {
+	fs.files = map[string]*tar.Header{}
+	fs.contents = map[string][]byte{}
+
+	for _, layer := range layers {
+		if err := fs.applyLayer(layer, wanted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fs *imageFS) applyLayer(layer v1.Layer, wanted map[string]bool) error {
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return errors.Wrap(err, "reading layer")
+	}
+	defer rc.Close()
+
+	// Whiteouts only apply to the lower layers.
+	added := map[string]bool{}
+
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading layer")
+		}
+
+		p := cleanPath(hdr.Name)
+		dir, base := path.Split(p)
+
+		switch {
+		case base == opaqueWhiteout:
+			fs.remove(path.Clean(dir), added, false)
+
+		case strings.HasPrefix(base, whiteoutPrefix):
+			fs.remove(path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)), added, true)
+
+		default:
+			fs.files[p] = hdr
+			delete(fs.contents, p)
+			added[p] = true
+			fs.addParents(p, added)
+
+			if wanted[p] && isRegular(hdr) {
+				if fs.contents[p], err = ioutil.ReadAll(tr); err != nil {
+					return errors.Wrapf(err, "reading %s", p)
+				}
+			}
+		}
+	}
+}
+
+// addParents adds the parent directories of a file that are not
+// explicitly listed in the layers.
+func (fs *imageFS) addParents(p string, added map[string]bool) {
+	for dir := path.Dir(p); dir != "/"; dir = path.Dir(dir) {
+		if _, found := fs.files[dir]; found {
+			return
+		}
+
+		fs.files[dir] = &tar.Header{Name: dir, Typeflag: tar.TypeDir, Mode: 0755}
+		added[dir] = true
+	}
+}
+
+// remove removes a file and its children, or only its children.
+func (fs *imageFS) remove(p string, added map[string]bool, self bool) {
+	prefix := strings.TrimSuffix(p, "/") + "/"
+
+	for name := range fs.files {
+		if added[name] {
+			continue
+		}
+		if (self && name == p) || strings.HasPrefix(name, prefix) {
+			delete(fs.files, name)
+			delete(fs.contents, name)
+		}
+	}
+}
+
+// lookup finds a file, following symbolic links. It returns the
+// resolved path of the file and nil if it doesn't exist.
+func (fs *imageFS) lookup(p string) (string, *tar.Header) {
+	parts := splitPath(p)
+	resolved := "/"
+	hops := 0
+
+	for i := 0; i < len(parts); i++ {
+		next := path.Join(resolved, parts[i])
+
+		hdr, found := fs.files[next]
+		if !found {
+			return next, nil
+		}
+
+		if hdr.Typeflag == tar.TypeSymlink {
+			hops++
+			if hops > maxSymlinks {
+				return next, nil
+			}
+
+			target := hdr.Linkname
+			if !path.IsAbs(target) {
+				target = path.Join(resolved, target)
+			}
+
+			parts = append(splitPath(target), parts[i+1:]...)
+			resolved = "/"
+			i = -1
+			continue
+		}
+
+		resolved = next
+	}
+
+	if resolved == "/" {
+		return resolved, &tar.Header{Name: "/", Typeflag: tar.TypeDir, Mode: 0755}
+	}
+	return resolved, fs.files[resolved]
+}
+
+// contentPath gives the path of the file that holds the contents of
+// the given file, after following symbolic and hard links.
+func (fs *imageFS) contentPath(p string) string {
+	resolved, hdr := fs.lookup(p)
+	if hdr != nil && hdr.Typeflag == tar.TypeLink {
+		return cleanPath(hdr.Linkname)
+	}
+	return resolved
+}
+
+// content returns the contents of a regular file.
+func (fs *imageFS) content(p string) ([]byte, bool) {
+	buf, found := fs.contents[fs.contentPath(p)]
+	return buf, found
+}
+
+func isRegular(hdr *tar.Header) bool {
+	return hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeRegA
+}
+
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+func splitPath(p string) []string {
+	var parts []string
+	for _, part := range strings.Split(cleanPath(p), "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
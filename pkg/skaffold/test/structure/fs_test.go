@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structure
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+type fakeImage struct {
+	v1.Image
+	config *v1.ConfigFile
+	layers []v1.Layer
+}
+
+func (i *fakeImage) ConfigFile() (*v1.ConfigFile, error) { return i.config, nil }
+func (i *fakeImage) Layers() ([]v1.Layer, error)         { return i.layers, nil }
+
+type fakeLayer struct {
+	v1.Layer
+	content []byte
+}
+
+func (l *fakeLayer) Uncompressed() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(l.content)), nil
+}
+
+type entry struct {
+	name     string
+	content  string
+	typeflag byte
+	linkname string
+	mode     int64
+}
+
+func file(name, content string) entry { return entry{name: name, content: content, mode: 0644} }
+func dir(name string) entry           { return entry{name: name, typeflag: tar.TypeDir, mode: 0755} }
+func symlink(name, target string) entry {
+	return entry{name: name, typeflag: tar.TypeSymlink, linkname: target, mode: 0777}
+}
+
+func layer(t *testing.T, entries ...entry) v1.Layer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, e := range entries {
+		typeflag := e.typeflag
+		if typeflag == 0 {
+			typeflag = tar.TypeReg
+		}
+
+		err := tw.WriteHeader(&tar.Header{
+			Name:     e.name,
+			Typeflag: typeflag,
+			Linkname: e.linkname,
+			Mode:     e.mode,
+			Size:     int64(len(e.content)),
+		})
+		testutil.CheckError(t, false, err)
+
+		_, err = tw.Write([]byte(e.content))
+		testutil.CheckError(t, false, err)
+	}
+	testutil.CheckError(t, false, tw.Close())
+
+	return &fakeLayer{content: buf.Bytes()}
+}
+
+func TestReadImageFS(t *testing.T) {
+	img := &fakeImage{
+		layers: []v1.Layer{
+			layer(t,
+				dir("etc/"),
+				file("etc/config", "v1"),
+				file("etc/removed", ""),
+				dir("var/lib/"),
+				file("var/lib/old", ""),
+				dir("usr/lib/"),
+				file("usr/lib/libc.so", "libc"),
+			),
+			layer(t,
+				file("etc/config", "v2"),
+				file("etc/.wh.removed", ""),
+				file("var/lib/.wh..wh..opq", ""),
+				file("var/lib/new", ""),
+				symlink("lib", "usr/lib"),
+				symlink("etc/link", "../etc/config"),
+			),
+		},
+	}
+
+	fs, err := readImageFS(img, []string{"/etc/link", "/lib/libc.so"})
+	testutil.CheckError(t, false, err)
+
+	tests := []struct {
+		path            string
+		shouldExist     bool
+		expectedContent string
+	}{
+		{path: "/", shouldExist: true},
+		{path: "/etc/config", shouldExist: true},
+		{path: "/etc/removed"},
+		{path: "/var/lib/old"},
+		{path: "/var/lib/new", shouldExist: true},
+		{path: "/lib/libc.so", shouldExist: true, expectedContent: "libc"},
+		{path: "/etc/link", shouldExist: true, expectedContent: "v2"},
+		{path: "/lib/unknown"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			_, hdr := fs.lookup(test.path)
+			testutil.CheckDeepEqual(t, test.shouldExist, hdr != nil)
+
+			if test.expectedContent != "" {
+				content, found := fs.content(test.path)
+				testutil.CheckDeepEqual(t, true, found)
+				testutil.CheckDeepEqual(t, test.expectedContent, string(content))
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structure
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/report"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// Permission bits checked by `isExecutableBy`.
+var executableBits = map[string]int64{
+	"any":   0111,
+	"owner": 0100,
+	"group": 0010,
+	"other": 0001,
+}
+
+// checks accumulates the errors of a single test.
+type checks struct {
+	name   string
+	start  time.Time
+	errors []string
+}
+
+func newChecks(name string) *checks {
+	return &checks{
+		name:  name,
+		start: time.Now(),
+	}
+}
+
+func (c *checks) errorf(format string, args ...interface{}) {
+	c.errors = append(c.errors, fmt.Sprintf(format, args...))
+}
+
+func (c *checks) result(image string) *report.Result {
+	return &report.Result{
+		Image:    image,
+		Runner:   "structure",
+		Name:     c.name,
+		Duration: time.Since(c.start),
+		Passed:   len(c.errors) == 0,
+		Output:   strings.Join(c.errors, "\n"),
+	}
+}
+
+func checkFileExistence(fs *imageFS, t *fileExistenceTest) *checks {
+	c := newChecks(t.Name)
+
+	_, hdr := fs.lookup(t.Path)
+	switch {
+	case hdr == nil && t.ShouldExist:
+		c.errorf("File %s should exist but does not", t.Path)
+		return c
+	case hdr != nil && !t.ShouldExist:
+		c.errorf("File %s should not exist but does", t.Path)
+		return c
+	case hdr == nil:
+		return c
+	}
+
+	mode := hdr.FileInfo().Mode()
+	if t.Permissions != "" && mode.String() != t.Permissions {
+		c.errorf("%s has incorrect permissions. Expected: %s, Actual: %s", t.Path, t.Permissions, mode.String())
+	}
+	if t.UID != nil && hdr.Uid != *t.UID {
+		c.errorf("%s has incorrect user ownership. Expected: %d, Actual: %d", t.Path, *t.UID, hdr.Uid)
+	}
+	if t.GID != nil && hdr.Gid != *t.GID {
+		c.errorf("%s has incorrect group ownership. Expected: %d, Actual: %d", t.Path, *t.GID, hdr.Gid)
+	}
+	if t.IsExecutableBy != "" {
+		bits, found := executableBits[t.IsExecutableBy]
+		switch {
+		case !found:
+			c.errorf("Unknown value for isExecutableBy: %s", t.IsExecutableBy)
+		case hdr.Mode&bits == 0:
+			c.errorf("%s is not executable by %s", t.Path, t.IsExecutableBy)
+		}
+	}
+
+	return c
+}
+
+func checkFileContent(fs *imageFS, t *fileContentTest) *checks {
+	c := newChecks(t.Name)
+
+	_, hdr := fs.lookup(t.Path)
+	content, found := fs.content(t.Path)
+	if hdr == nil || !found {
+		c.errorf("Failed to read %s: not a regular file", t.Path)
+		return c
+	}
+
+	for _, expected := range t.ExpectedContents {
+		if !matches(c, expected, string(content)) {
+			c.errorf("Expected string '%s' not found in file content", expected)
+		}
+	}
+	for _, excluded := range t.ExcludedContents {
+		if matches(c, excluded, string(content)) {
+			c.errorf("Excluded string '%s' found in file content", excluded)
+		}
+	}
+
+	return c
+}
+
+func checkMetadata(cfg *v1.ConfigFile, t *metadataTest) *checks {
+	c := newChecks("Metadata Test")
+	config := cfg.Config
+
+	env := map[string]string{}
+	for _, kv := range config.Env {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 {
+			env[parts[0]] = parts[1]
+		}
+	}
+	checkKeyValues(c, "env var", t.Env, env)
+	checkKeyValues(c, "label", t.Labels, config.Labels)
+
+	for _, port := range t.ExposedPorts {
+		if !hasPort(config.ExposedPorts, port) {
+			c.errorf("Port %s not found in config", port)
+		}
+	}
+	for _, port := range t.UnexposedPorts {
+		if hasPort(config.ExposedPorts, port) {
+			c.errorf("Port %s should not be exposed", port)
+		}
+	}
+
+	for _, volume := range t.Volumes {
+		if _, found := config.Volumes[volume]; !found {
+			c.errorf("Volume %s not found in config", volume)
+		}
+	}
+	for _, volume := range t.UnmountedVolumes {
+		if _, found := config.Volumes[volume]; found {
+			c.errorf("Volume %s should not be mounted", volume)
+		}
+	}
+
+	if t.Entrypoint != nil && !equalStrings(*t.Entrypoint, config.Entrypoint) {
+		c.errorf("Image entrypoint %v does not match expected entrypoint: %v", config.Entrypoint, *t.Entrypoint)
+	}
+	if t.Cmd != nil && !equalStrings(*t.Cmd, config.Cmd) {
+		c.errorf("Image cmd %v does not match expected cmd: %v", config.Cmd, *t.Cmd)
+	}
+	if t.Workdir != "" && t.Workdir != config.WorkingDir {
+		c.errorf("Image workdir %s does not match config workdir: %s", config.WorkingDir, t.Workdir)
+	}
+	if t.User != "" && t.User != config.User {
+		c.errorf("Image user %s does not match config user: %s", config.User, t.User)
+	}
+
+	return c
+}
+
+func checkKeyValues(c *checks, kind string, expected []*keyValue, actual map[string]string) {
+	for _, kv := range expected {
+		value, found := actual[kv.Key]
+		switch {
+		case !found:
+			c.errorf("%s %s not found in config", kind, kv.Key)
+		case kv.IsRegex && !matches(c, kv.Value, value):
+			c.errorf("%s %s value %s does not match expected pattern: %s", kind, kv.Key, value, kv.Value)
+		case !kv.IsRegex && value != kv.Value:
+			c.errorf("%s %s value %s does not match expected value: %s", kind, kv.Key, value, kv.Value)
+		}
+	}
+}
+
+// matches evaluates a regexp, recording an error if it's invalid.
+func matches(c *checks, pattern, s string) bool {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		c.errorf("Invalid regex %s: %s", pattern, err)
+		return false
+	}
+	return re.MatchString(s)
+}
+
+// hasPort checks for a port, with or without its protocol.
+func hasPort(ports map[string]struct{}, port string) bool {
+	for exposed := range ports {
+		if exposed == port || strings.TrimSuffix(exposed, "/tcp") == port {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structure
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+func TestCheckFileExistence(t *testing.T) {
+	img := &fakeImage{
+		layers: []v1.Layer{
+			layer(t,
+				entry{name: "bin/app", content: "#!/bin/sh", mode: 0750},
+				file("etc/config", ""),
+			),
+		},
+	}
+	fs, err := readImageFS(img, nil)
+	testutil.CheckError(t, false, err)
+
+	zero := 0
+	tests := []struct {
+		description string
+		test        *fileExistenceTest
+		expected    []string
+	}{
+		{
+			description: "exists",
+			test:        &fileExistenceTest{Path: "/bin/app", ShouldExist: true, Permissions: "-rwxr-x---", UID: &zero, GID: &zero, IsExecutableBy: "group"},
+		},
+		{
+			description: "should not exist",
+			test:        &fileExistenceTest{Path: "/bin/app"},
+			expected:    []string{"File /bin/app should not exist but does"},
+		},
+		{
+			description: "missing",
+			test:        &fileExistenceTest{Path: "/bin/other", ShouldExist: true},
+			expected:    []string{"File /bin/other should exist but does not"},
+		},
+		{
+			description: "wrong permissions",
+			test:        &fileExistenceTest{Path: "/etc/config", ShouldExist: true, Permissions: "-rwxrwxrwx", IsExecutableBy: "any"},
+			expected: []string{
+				"/etc/config has incorrect permissions. Expected: -rwxrwxrwx, Actual: -rw-r--r--",
+				"/etc/config is not executable by any",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := checkFileExistence(fs, test.test)
+
+			testutil.CheckDeepEqual(t, test.expected, c.errors)
+		})
+	}
+}
+
+func TestCheckFileContent(t *testing.T) {
+	img := &fakeImage{
+		layers: []v1.Layer{
+			layer(t,
+				file("etc/apt/sources.list", "deb http://deb.debian.org/debian stretch main"),
+				dir("etc/apt/sources.list.d"),
+			),
+		},
+	}
+	fs, err := readImageFS(img, []string{"/etc/apt/sources.list", "/etc/apt/sources.list.d"})
+	testutil.CheckError(t, false, err)
+
+	tests := []struct {
+		description string
+		test        *fileContentTest
+		expected    []string
+	}{
+		{
+			description: "matching content",
+			test: &fileContentTest{
+				Path:             "/etc/apt/sources.list",
+				ExpectedContents: []string{".*deb\\.debian\\.org.*"},
+				ExcludedContents: []string{"jessie"},
+			},
+		},
+		{
+			description: "mismatching content",
+			test: &fileContentTest{
+				Path:             "/etc/apt/sources.list",
+				ExpectedContents: []string{"jessie"},
+				ExcludedContents: []string{"stretch"},
+			},
+			expected: []string{
+				"Expected string 'jessie' not found in file content",
+				"Excluded string 'stretch' found in file content",
+			},
+		},
+		{
+			description: "directory",
+			test:        &fileContentTest{Path: "/etc/apt/sources.list.d"},
+			expected:    []string{"Failed to read /etc/apt/sources.list.d: not a regular file"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := checkFileContent(fs, test.test)
+
+			testutil.CheckDeepEqual(t, test.expected, c.errors)
+		})
+	}
+}
+
+func TestCheckMetadata(t *testing.T) {
+	cfg := &v1.ConfigFile{
+		Config: v1.Config{
+			Env:          []string{"PATH=/usr/bin", "VERSION=1.2.3"},
+			Labels:       map[string]string{"vendor": "ACME"},
+			ExposedPorts: map[string]struct{}{"8080/tcp": {}},
+			Volumes:      map[string]struct{}{"/data": {}},
+			Entrypoint:   []string{"/app"},
+			WorkingDir:   "/",
+			User:         "nobody",
+		},
+	}
+
+	tests := []struct {
+		description string
+		test        *metadataTest
+		expected    []string
+	}{
+		{
+			description: "matching metadata",
+			test: &metadataTest{
+				Env:              []*keyValue{{Key: "PATH", Value: "/usr/bin"}, {Key: "VERSION", Value: "1\\..*", IsRegex: true}},
+				Labels:           []*keyValue{{Key: "vendor", Value: "ACME"}},
+				ExposedPorts:     []string{"8080"},
+				UnexposedPorts:   []string{"9090"},
+				Volumes:          []string{"/data"},
+				UnmountedVolumes: []string{"/tmp"},
+				Entrypoint:       &[]string{"/app"},
+				Cmd:              &[]string{},
+				Workdir:          "/",
+				User:             "nobody",
+			},
+		},
+		{
+			description: "mismatching metadata",
+			test: &metadataTest{
+				Env:            []*keyValue{{Key: "HOME", Value: "/root"}},
+				Labels:         []*keyValue{{Key: "vendor", Value: "Other"}},
+				UnexposedPorts: []string{"8080/tcp"},
+				Entrypoint:     &[]string{"/bin/sh"},
+				User:           "root",
+			},
+			expected: []string{
+				"env var HOME not found in config",
+				"label vendor value ACME does not match expected value: Other",
+				"Port 8080/tcp should not be exposed",
+				"Image entrypoint [/app] does not match expected entrypoint: [/bin/sh]",
+				"Image user nobody does not match config user: root",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := checkMetadata(cfg, test.test)
+
+			testutil.CheckDeepEqual(t, test.expected, c.errors)
+		})
+	}
+}
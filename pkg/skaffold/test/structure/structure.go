@@ -17,31 +17,282 @@ limitations under the License.
 package structure
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/report"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// For testing
+var (
+	localDaemon = docker.NewAPIClient
+	remoteImage = docker.RemoteImage
 )
 
 // Test is the entrypoint for running structure tests
 func (tr *Runner) Test(ctx context.Context, out io.Writer, image string) error {
+	_, err := tr.TestWithResults(ctx, out, image)
+	return err
+}
+
+// TestWithResults runs the structure tests and returns the result of each test.
+// File and metadata tests are evaluated by reading the image's config and layers.
+// The other tests, like command tests, need to run a container and are
+// delegated to container-structure-test.
+func (tr *Runner) TestWithResults(ctx context.Context, out io.Writer, image string) ([]*report.Result, error) {
 	logrus.Infof("Running structure tests for files %v", tr.testFiles)
 
+	tmpDir, err := ioutil.TempDir("", "structure")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	testFiles := map[string]*testFile{}
+	var (
+		hasMetadataTests bool
+		contentPaths     []string
+		hasFileTests     bool
+	)
+	for _, path := range tr.testFiles {
+		tf, err := readTestFile(path)
+		if err != nil {
+			return nil, err
+		}
+		testFiles[path] = tf
+
+		hasMetadataTests = hasMetadataTests || tf.MetadataTest != nil
+		hasFileTests = hasFileTests || tf.hasFileTests()
+		for _, t := range tf.FileContentTests {
+			contentPaths = append(contentPaths, t.Path)
+		}
+	}
+
+	var cfg *v1.ConfigFile
+	if hasMetadataTests {
+		if cfg, err = configFile(ctx, image); err != nil {
+			return nil, errors.Wrapf(err, "getting config of %s", image)
+		}
+	}
+
+	var fs *imageFS
+	if hasFileTests {
+		fs, err = fileSystem(ctx, image, filepath.Join(tmpDir, "image.tar"), contentPaths)
+		if err != nil {
+			logrus.Infof("Unable to read the layers of %s, the file tests will be run by container-structure-test: %s", image, err)
+		}
+	}
+
+	var (
+		results []*report.Result
+		configs []string
+	)
+	for i, path := range tr.testFiles {
+		tf := testFiles[path]
+
+		if tf.MetadataTest != nil {
+			results = append(results, checkMetadata(cfg, tf.MetadataTest).result(image))
+		}
+		if fs != nil {
+			for _, t := range tf.FileExistenceTests {
+				results = append(results, checkFileExistence(fs, t).result(image))
+			}
+			for _, t := range tf.FileContentTests {
+				results = append(results, checkFileContent(fs, t).result(image))
+			}
+		}
+
+		remaining := tf.remaining(fs == nil)
+		switch {
+		case remaining == nil:
+		case len(remaining) == len(tf.raw):
+			configs = append(configs, path)
+		default:
+			config := filepath.Join(tmpDir, fmt.Sprintf("structure-test-%d.yaml", i))
+			if err := writeTestFile(config, remaining); err != nil {
+				return nil, err
+			}
+			configs = append(configs, config)
+		}
+	}
+
+	var runErr error
+	if len(configs) > 0 {
+		var binaryResults []*report.Result
+		binaryResults, runErr = runStructureTests(ctx, image, configs)
+		results = append(results, binaryResults...)
+	}
+
+	failures := printResults(out, results)
+	if failures > 0 {
+		return results, fmt.Errorf("running structure tests: %d of %d tests failed", failures, len(results))
+	}
+	if runErr != nil {
+		return results, runErr
+	}
+
+	return results, nil
+}
+
+// configFile reads the config of an image from the local daemon or the registry.
+func configFile(ctx context.Context, image string) (*v1.ConfigFile, error) {
+	if localDocker, err := localDaemon(); err == nil {
+		return localDocker.ConfigFile(ctx, image)
+	}
+
+	img, err := remoteImage(image)
+	if err != nil {
+		return nil, err
+	}
+	return img.ConfigFile()
+}
+
+// fileSystem reads the layers of an image from the local daemon, or from
+// the registry if the image is not found locally.
+func fileSystem(ctx context.Context, image, tarPath string, contentPaths []string) (*imageFS, error) {
+	img, err := localImage(ctx, image, tarPath)
+	if err != nil {
+		logrus.Debugf("Unable to save %s from the local daemon: %s", image, err)
+
+		if img, err = remoteImage(image); err != nil {
+			return nil, err
+		}
+	}
+
+	return readImageFS(img, contentPaths)
+}
+
+func localImage(ctx context.Context, image, tarPath string) (v1.Image, error) {
+	localDocker, err := localDaemon()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := localDocker.Save(ctx, image, tarPath); err != nil {
+		return nil, err
+	}
+
+	return tarball.ImageFromPath(tarPath, nil)
+}
+
+func writeTestFile(path string, content yaml.MapSlice) error {
+	buf, err := yaml.Marshal(content)
+	if err != nil {
+		return errors.Wrap(err, "marshalling structure tests")
+	}
+
+	return ioutil.WriteFile(path, buf, 0644)
+}
+
+// summary is the JSON output of container-structure-test.
+type summary struct {
+	Pass    int
+	Fail    int
+	Total   int
+	Results []*result
+}
+
+type result struct {
+	Name     string
+	Pass     bool
+	Stdout   string
+	Stderr   string
+	Errors   []string
+	Duration time.Duration
+}
+
+// runStructureTests runs container-structure-test with a JSON output and
+// returns the result of each test.
+func runStructureTests(ctx context.Context, image string, configs []string) ([]*report.Result, error) {
 	args := []string{"test", "-v", "warn", "--image", image}
-	for _, f := range tr.testFiles {
-		args = append(args, "--config", f)
+	for _, config := range configs {
+		args = append(args, "--config", config)
 	}
+	args = append(args, "--output", "json")
 
 	cmd := exec.CommandContext(ctx, "container-structure-test", args...)
-	cmd.Stdout = out
-	cmd.Stderr = out
+	stdout, runErr := util.RunCmdOut(cmd)
+
+	s, err := parseSummary(stdout)
+	if err != nil {
+		if runErr != nil {
+			return nil, errors.Wrap(runErr, "running container-structure-test")
+		}
+		return nil, errors.Wrap(err, "parsing container-structure-test output")
+	}
+
+	var results []*report.Result
+	for _, r := range s.Results {
+		output := strings.Join(append(r.Errors, r.Stdout, r.Stderr), "\n")
+		results = append(results, &report.Result{
+			Image:    image,
+			Runner:   "structure",
+			Name:     r.Name,
+			Duration: r.Duration,
+			Passed:   r.Pass,
+			Output:   strings.TrimSpace(output),
+		})
+	}
+
+	if runErr != nil && s.Fail == 0 {
+		return results, errors.Wrap(runErr, "running container-structure-test")
+	}
+
+	return results, nil
+}
+
+// parseSummary extracts the JSON summary from the output, which can
+// be preceded by log lines.
+func parseSummary(output []byte) (*summary, error) {
+	start := bytes.IndexByte(output, '{')
+	if start < 0 {
+		return nil, errors.New("no test results found")
+	}
+
+	var s summary
+	if err := json.Unmarshal(output[start:], &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// printResults prints the outcome of each test and returns the number of failures.
+func printResults(out io.Writer, results []*report.Result) int {
+	failures := 0
+
+	for _, r := range results {
+		if r.Passed {
+			color.Green.Fprintf(out, "PASS: %s\n", r.Name)
+			continue
+		}
 
-	if err := util.RunCmd(cmd); err != nil {
-		return errors.Wrap(err, "running container-structure-test")
+		failures++
+		color.Red.Fprintf(out, "FAIL: %s\n", r.Name)
+		for _, line := range strings.Split(r.Output, "\n") {
+			if line != "" {
+				fmt.Fprintf(out, "  %s\n", line)
+			}
+		}
 	}
+	fmt.Fprintf(out, "Passes: %d, Failures: %d, Total: %d\n", len(results)-failures, failures, len(results))
 
-	return nil
+	return failures
 }
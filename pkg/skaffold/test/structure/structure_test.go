@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structure
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/report"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+const (
+	fileTests = `schemaVersion: 2.0.0
+fileExistenceTests:
+- name: app
+  path: /app
+  shouldExist: true
+fileContentTests:
+- name: config
+  path: /config
+  expectedContents: [debug]
+`
+	metadataTests = `schemaVersion: 2.0.0
+metadataTest:
+  env:
+  - key: DEBUG
+    value: "true"
+`
+	commandTests = `schemaVersion: 2.0.0
+commandTests:
+- name: ls
+  command: ls
+`
+)
+
+func TestTestWithResults(t *testing.T) {
+	img := &fakeImage{
+		config: &v1.ConfigFile{Config: v1.Config{Env: []string{"DEBUG=false"}}},
+		layers: []v1.Layer{
+			layer(t, file("app", ""), file("config", "debug=true")),
+		},
+	}
+
+	tests := []struct {
+		description string
+		testFile    string
+		remoteErr   error
+		output      string
+		shouldErr   bool
+		expected    []*report.Result
+	}{
+		{
+			description: "file tests",
+			testFile:    fileTests,
+			expected: []*report.Result{
+				{Image: "img", Runner: "structure", Name: "app", Passed: true},
+				{Image: "img", Runner: "structure", Name: "config", Passed: true},
+			},
+		},
+		{
+			description: "metadata tests",
+			testFile:    metadataTests,
+			shouldErr:   true,
+			expected: []*report.Result{
+				{Image: "img", Runner: "structure", Name: "Metadata Test", Output: "env var DEBUG value false does not match expected value: true"},
+			},
+		},
+		{
+			description: "command tests",
+			testFile:    commandTests,
+			output:      `{"Pass":1,"Fail":0,"Total":1,"Results":[{"Name":"ls","Pass":true,"Duration":2000}]}`,
+			expected: []*report.Result{
+				{Image: "img", Runner: "structure", Name: "ls", Passed: true},
+			},
+		},
+		{
+			description: "file tests without access to the image",
+			testFile:    fileTests,
+			remoteErr:   errors.New("not found"),
+			output: `level=warn msg="warning"
+{"Pass":1,"Fail":1,"Total":2,"Results":[{"Name":"app","Pass":true},{"Name":"config","Pass":false,"Errors":["not found"]}]}`,
+			shouldErr: true,
+			expected: []*report.Result{
+				{Image: "img", Runner: "structure", Name: "app", Passed: true},
+				{Image: "img", Runner: "structure", Name: "config", Passed: false, Output: "not found"},
+			},
+		},
+		{
+			description: "invalid output",
+			testFile:    commandTests,
+			output:      "invalid config",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmpDir, cleanup := testutil.NewTempDir(t)
+			defer cleanup()
+			tmpDir.Write("test.yaml", test.testFile)
+
+			defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+			command := testutil.NewFakeCmd(t)
+			if test.output != "" {
+				command.WithRunOut("container-structure-test test -v warn --image img --config "+tmpDir.Path("test.yaml")+" --output json", test.output)
+			}
+			util.DefaultExecCommand = command
+
+			defer func(l func() (docker.LocalDaemon, error)) { localDaemon = l }(localDaemon)
+			localDaemon = func() (docker.LocalDaemon, error) { return nil, errors.New("no daemon") }
+
+			defer func(r func(string) (v1.Image, error)) { remoteImage = r }(remoteImage)
+			remoteImage = func(string) (v1.Image, error) { return img, test.remoteErr }
+
+			results, err := NewRunner([]string{tmpDir.Path("test.yaml")}).TestWithResults(context.Background(), ioutil.Discard, "img")
+
+			// Durations are not predictable
+			for _, r := range results {
+				r.Duration = 0
+			}
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, results)
+		})
+	}
+}
@@ -17,31 +17,48 @@ limitations under the License.
 package test
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"os"
+	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/custom"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/report"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/structure"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // NewTester parses the provided test cases from the Skaffold config,
 // and returns a Tester instance with all the necessary test runners
 // to run all specified tests.
-func NewTester(testCases []*latest.TestCase) (Tester, error) {
+func NewTester(testCases []*latest.TestCase, opts *config.SkaffoldOptions) (Tester, error) {
 	// TODO(nkubala): copied this from runner.getDeployer(), this should be moved somewhere else
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, errors.Wrap(err, "finding current directory")
 	}
 
+	var cache *testCache
+	if opts.CacheTests {
+		cache, err = newTestCache(opts.TestCacheFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating test cache")
+		}
+	}
+
 	return FullTester{
 		testCases:  testCases,
 		workingDir: cwd,
+		reportFile: opts.TestReport,
+		cache:      cache,
 	}, nil
 }
 
@@ -50,34 +67,116 @@ func (t FullTester) TestDependencies() ([]string, error) {
 	var deps []string
 
 	for _, test := range t.testCases {
-		if test.StructureTests == nil {
-			continue
+		files, err := t.dependencies(test)
+		if err != nil {
+			return nil, err
 		}
 
-		files, err := util.ExpandPathsGlob(t.workingDir, test.StructureTests)
+		deps = append(deps, files...)
+	}
+
+	return deps, nil
+}
+
+func (t FullTester) dependencies(testCase *latest.TestCase) ([]string, error) {
+	var deps []string
+
+	for _, ct := range testCase.CustomTests {
+		files, err := custom.NewRunner(ct, t.workingDir).Dependencies()
 		if err != nil {
-			return nil, errors.Wrap(err, "expanding test file paths")
+			return nil, errors.Wrap(err, "expanding custom test dependencies")
 		}
 
 		deps = append(deps, files...)
 	}
 
-	return deps, nil
+	if testCase.StructureTests == nil {
+		return deps, nil
+	}
+
+	files, err := util.ExpandPathsGlob(t.workingDir, testCase.StructureTests)
+	if err != nil {
+		return nil, errors.Wrap(err, "expanding test file paths")
+	}
+
+	return append(deps, files...), nil
 }
 
 // Test is the top level testing execution call. It serves as the
 // entrypoint to all individual tests.
 func (t FullTester) Test(ctx context.Context, out io.Writer, bRes []build.Artifact) error {
+	var results []*report.Result
+	err := t.runTests(ctx, out, bRes, &results)
+
+	if t.reportFile != "" {
+		if reportErr := report.Write(t.reportFile, results); reportErr != nil {
+			if err == nil {
+				return reportErr
+			}
+			logrus.Warnln("Unable to write test report:", reportErr)
+		}
+	}
+
+	return err
+}
+
+func (t FullTester) runTests(ctx context.Context, out io.Writer, bRes []build.Artifact, results *[]*report.Result) error {
 	for _, test := range t.testCases {
-		if err := t.runStructureTests(ctx, out, bRes, test); err != nil {
+		key := t.cacheKey(ctx, bRes, test)
+		if key != "" && t.cache.hasPassed(key) {
+			fqn := resolveArtifactImageTag(test.ImageName, bRes)
+			color.Default.Fprintf(out, "Testing [%s]: cached: passed\n", fqn)
+			*results = append(*results, &report.Result{
+				Image:  fqn,
+				Runner: "cache",
+				Name:   test.ImageName,
+				Passed: true,
+				Output: "cached: passed",
+			})
+			continue
+		}
+
+		if err := t.runStructureTests(ctx, out, bRes, test, results); err != nil {
 			return errors.Wrap(err, "running structure tests")
 		}
+
+		if err := t.runCustomTests(ctx, out, bRes, test, results); err != nil {
+			return errors.Wrap(err, "running custom tests")
+		}
+
+		if key != "" {
+			if err := t.cache.retainPass(key); err != nil {
+				logrus.Warnln("Unable to save the test cache:", err)
+			}
+		}
 	}
 
 	return nil
 }
 
-func (t FullTester) runStructureTests(ctx context.Context, out io.Writer, bRes []build.Artifact, testCase *latest.TestCase) error {
+// cacheKey identifies the tests of an image in the cache. An empty key
+// is returned if the tests can't be cached.
+func (t FullTester) cacheKey(ctx context.Context, bRes []build.Artifact, testCase *latest.TestCase) string {
+	if t.cache == nil {
+		return ""
+	}
+
+	files, err := t.dependencies(testCase)
+	if err != nil {
+		logrus.Debugf("Unable to list the test files of %s, tests won't be cached: %s", testCase.ImageName, err)
+		return ""
+	}
+
+	key, err := cacheKey(ctx, resolveArtifactImageTag(testCase.ImageName, bRes), testCase, files)
+	if err != nil {
+		logrus.Debugf("Unable to compute the test cache key of %s, tests won't be cached: %s", testCase.ImageName, err)
+		return ""
+	}
+
+	return key
+}
+
+func (t FullTester) runStructureTests(ctx context.Context, out io.Writer, bRes []build.Artifact, testCase *latest.TestCase, results *[]*report.Result) error {
 	if len(testCase.StructureTests) == 0 {
 		return nil
 	}
@@ -90,7 +189,37 @@ func (t FullTester) runStructureTests(ctx context.Context, out io.Writer, bRes [
 	runner := structure.NewRunner(files)
 	fqn := resolveArtifactImageTag(testCase.ImageName, bRes)
 
-	return runner.Test(ctx, out, fqn)
+	structureResults, err := runner.TestWithResults(ctx, out, fqn)
+	*results = append(*results, structureResults...)
+	return err
+}
+
+func (t FullTester) runCustomTests(ctx context.Context, out io.Writer, bRes []build.Artifact, testCase *latest.TestCase, results *[]*report.Result) error {
+	fqn := resolveArtifactImageTag(testCase.ImageName, bRes)
+
+	for _, ct := range testCase.CustomTests {
+		var output bytes.Buffer
+		start := time.Now()
+		err := custom.NewRunner(ct, t.workingDir).Test(ctx, io.MultiWriter(out, &output), fqn)
+		if err != nil {
+			output.WriteString(err.Error())
+		}
+
+		*results = append(*results, &report.Result{
+			Image:    fqn,
+			Runner:   "custom",
+			Name:     ct.Command,
+			Duration: time.Since(start),
+			Passed:   err == nil,
+			Output:   output.String(),
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func resolveArtifactImageTag(imageName string, bRes []build.Artifact) string {
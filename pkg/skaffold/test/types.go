@@ -44,6 +44,8 @@ type Tester interface {
 type FullTester struct {
 	testCases  []*latest.TestCase
 	workingDir string
+	reportFile string
+	cache      *testCache
 }
 
 // Runner is the lowest-level test executor in Skaffold, responsible for
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ListFiles lists the files, relative to the workspace, that match the given
+// paths or glob patterns and are not excluded by the ignore patterns.
+// Directories are walked recursively.
+func ListFiles(workspace string, paths, ignore []string) ([]string, error) {
+	files := map[string]bool{}
+	for _, path := range paths {
+		matches, err := filepath.Glob(filepath.Join(workspace, path))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid dependency pattern %s", path)
+		}
+		if len(matches) == 0 {
+			logrus.Warnf("%s did not match any file", path)
+		}
+
+		for _, match := range matches {
+			if err := walkFiles(workspace, match, ignore, files); err != nil {
+				return nil, errors.Wrapf(err, "walking %s", match)
+			}
+		}
+	}
+
+	var list []string
+	for file := range files {
+		list = append(list, file)
+	}
+	sort.Strings(list)
+
+	return list, nil
+}
+
+// walkFiles collects the files found under root, relative to the workspace,
+// that are not ignored.
+func walkFiles(workspace, root string, ignore []string, files map[string]bool) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(workspace, path)
+		if err != nil {
+			return err
+		}
+
+		ignored, err := isIgnored(rel, ignore)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case ignored && info.IsDir():
+			return filepath.SkipDir
+		case ignored || info.IsDir():
+			return nil
+		default:
+			files[rel] = true
+			return nil
+		}
+	})
+}
+
+// isIgnored checks if a path, or one of its parent directories, matches
+// one of the ignore patterns.
+func isIgnored(path string, ignore []string) (bool, error) {
+	for _, pattern := range ignore {
+		pattern = filepath.Clean(pattern)
+
+		for p := path; p != "." && p != string(filepath.Separator); p = filepath.Dir(p) {
+			matches, err := filepath.Match(pattern, p)
+			if err != nil {
+				return false, errors.Wrapf(err, "invalid ignore pattern %s", pattern)
+			}
+			if matches {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
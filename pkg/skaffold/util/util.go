@@ -137,6 +137,12 @@ func BoolPtr(b bool) *bool {
 	return &o
 }
 
+// IntPtr returns a pointer to an int
+func IntPtr(i int) *int {
+	o := i
+	return &o
+}
+
 // StringPtr returns a pointer to a string
 func StringPtr(s string) *string {
 	o := s
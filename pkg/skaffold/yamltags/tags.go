@@ -156,8 +156,9 @@ func (oot *OneOfTag) Load(s []string) error {
 	}
 	oot.setName = s[1]
 
-	// Fetch the right oneOfSet for the struct.
-	structName := oot.Parent.Type().Name()
+	// Fetch the right oneOfSet for the struct. Structs with the same name
+	// exist in multiple schema versions, so the package path is part of the key.
+	structName := oot.Parent.Type().PkgPath() + "." + oot.Parent.Type().Name()
 	oot.oneOfSets = getOneOfSetsForStruct(structName)
 
 	// Add this field to the oneOfSet